@@ -12,11 +12,15 @@ import (
 )
 
 // Node represents a node in an adjacency graph, either directed or undirected.
-// It implements (for example) graph2.Node, graph2.HalfNode, graph2.EstimateNode,
-// and fmt.Stringer.
+// It implements (for example) graph2.Node, graph2.HalfNode, graph2.InHalfNode,
+// graph2.BiHalfNode, graph2.EstimateNode, and fmt.Stringer.
+//
+// Nbs holds half arcs leading from the node, In holds half arcs leading
+// to the node.  For undirected graphs, In mirrors Nbs.
 type Node struct {
 	Data interface{}
 	Nbs  []graph2.Half
+	In   []graph2.FromHalf
 }
 
 // VisitAdjNodes iterates over adjacent nodes, calling the visitor funcction
@@ -37,6 +41,33 @@ func (n *Node) NumAdj() int {
 	return len(n.Nbs)
 }
 
+// VisitBF2In iterates over nodes adjacent by inward arcs, calling the
+// visitor function for each.  It handles visitor results as documented for
+// graph2.BF2Node.
+func (n *Node) VisitBF2In(v graph2.BF2NeighborVisitor) bool {
+	for _, h := range n.In {
+		switch v(h.From.(*Node)) {
+		case graph2.BF2Stop:
+			return false
+		case graph2.BF2Found:
+			return true
+		}
+	}
+	return true
+}
+
+// VisitBF2Out iterates over nodes adjacent by outward arcs, calling the
+// visitor function for each.  It handles visitor results as documented for
+// graph2.BF2Node.
+func (n *Node) VisitBF2Out(v graph2.BF2NeighborVisitor) bool {
+	for _, h := range n.Nbs {
+		if v(h.To.(*Node)) == graph2.BF2Stop {
+			return false
+		}
+	}
+	return true
+}
+
 // VisitAdjHalfs visits adjacent half edges, calling the visitor funcction
 // for each.
 func (n *Node) VisitAdjHalfs(v graph2.AdjHalfVisitor) {
@@ -45,6 +76,14 @@ func (n *Node) VisitAdjHalfs(v graph2.AdjHalfVisitor) {
 	}
 }
 
+// VisitInHalfs visits half edges leading to the node, calling the visitor
+// function for each.
+func (n *Node) VisitInHalfs(v graph2.InHalfVisitor) {
+	for _, h := range n.In {
+		v(h)
+	}
+}
+
 // Estimate obtains a heuristic distance estimate through the Estimator
 // interface of the Data field of the receiver.  This panics if n.Data
 // does not impliment Estimator.  It does not default to a null heuristic.
@@ -70,6 +109,7 @@ type Digraph map[interface{}]*Node
 
 // Link sets one Node of a Digraph to be adjacent to another, adding either
 // or both nodes to the graph as neccessary and adding an arc linking them.
+// The arc is recorded in Nbs of the n1 node and in In of the n2 node.
 //
 // N1 and n2 are used as map keys and are also assigned to the Data fields
 // when nodes are first added to the graph2.  Because n1 and n2 are used as
@@ -85,12 +125,32 @@ func (g Digraph) Link(n1, n2 interface{}, arc graph2.Arc) {
 		nd2 = &Node{Data: n2}
 		g[n2] = nd2
 	}
-	if nd1, ok := g[n1]; !ok {
-		nd1 = &Node{n1, []graph2.Half{{arc, nd2}}}
+	nd1, ok := g[n1]
+	if !ok {
+		nd1 = &Node{Data: n1}
 		g[n1] = nd1
-	} else {
-		nd1.Nbs = append(nd1.Nbs, graph2.Half{arc, nd2})
 	}
+	nd1.Nbs = append(nd1.Nbs, graph2.Half{arc, nd2})
+	nd2.In = append(nd2.In, graph2.FromHalf{nd1, arc})
+}
+
+// Nodes returns a new map populated with all nodes in the graph.
+// Along with NumEdges it implements graph2.BF2Graph.
+func (g Digraph) Nodes() map[graph2.BF2Node]struct{} {
+	m := make(map[graph2.BF2Node]struct{}, len(g))
+	for _, nd := range g {
+		m[nd] = struct{}{}
+	}
+	return m
+}
+
+// NumEdges returns the number of arcs in the graph.
+func (g Digraph) NumEdges() int {
+	m := 0
+	for _, nd := range g {
+		m += len(nd.Nbs)
+	}
+	return m
 }
 
 type Graph struct {
@@ -100,8 +160,8 @@ type Graph struct {
 
 func NewGraph() Graph {
 	return Graph{
-		map[interface{}]*Node{},
-		map[struct{ n1, n2 *Node }]graph2.Edge{},
+		Nodes: map[interface{}]*Node{},
+		Edges: map[struct{ n1, n2 *Node }]graph2.Edge{},
 	}
 }
 
@@ -130,4 +190,29 @@ func (g Graph) Link(n1, n2 interface{}, ed graph2.Edge) {
 	g.Edges[struct{ n1, n2 *Node }{nd1, nd2}] = ed
 	nd1.Nbs = append(nd1.Nbs, graph2.Half{ed, nd2})
 	nd2.Nbs = append(nd2.Nbs, graph2.Half{ed, nd1})
+	nd1.In = append(nd1.In, graph2.FromHalf{nd2, ed})
+	nd2.In = append(nd2.In, graph2.FromHalf{nd1, ed})
+}
+
+// NumEdges returns the number of edges in the graph.
+func (g Graph) NumEdges() int {
+	return len(g.Edges)
+}
+
+// BF2Graph returns a graph2.BF2Graph for g, suitable for use with
+// search.BreadthFirst2.  (Graph cannot implement BF2Graph directly
+// because a method Nodes would collide with the field Nodes.)
+func (g Graph) BF2Graph() graph2.BF2Graph {
+	return bf2Graph{g}
+}
+
+// bf2Graph implements graph2.BF2Graph for an undirected Graph.
+type bf2Graph struct{ g Graph }
+
+func (b bf2Graph) Nodes() map[graph2.BF2Node]struct{} {
+	return Digraph(b.g.Nodes).Nodes()
+}
+
+func (b bf2Graph) NumEdges() int {
+	return len(b.g.Edges)
 }
@@ -65,3 +65,16 @@ func ExampleDigraph_Link() {
 	// adjacent to node 2: 3 1
 	// adjacent to node 3:
 }
+
+func ExampleNode_VisitInHalfs() {
+	g := adj.Digraph{}
+	g.Link(1, 3, nil)
+	g.Link(2, 3, nil)
+	g.Link(3, 1, nil)
+	g[3].VisitInHalfs(func(h graph2.FromHalf) {
+		fmt.Println("arc from", h.From)
+	})
+	// Output:
+	// arc from 1
+	// arc from 2
+}
@@ -0,0 +1,216 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"github.com/soniakeys/graph2"
+)
+
+// ArticulationPoints returns the articulation points of an undirected graph.
+//
+// An articulation point, or cut vertex, is a node whose removal, with its
+// edges, increases the number of connected components of the graph.
+// Self loops are ignored.  The order of the result is unspecified.
+func (g Graph) ArticulationPoints() []*Node {
+	return g.biconnect().art
+}
+
+// Bridges returns the bridges of an undirected graph.
+//
+// A bridge is an edge whose removal increases the number of connected
+// components of the graph.  Bridges are returned as the edge values stored
+// in g.Edges.  Self loops are ignored.  The order of the result is
+// unspecified.
+func (g Graph) Bridges() []graph2.Edge {
+	b := g.biconnect()
+	bridges := make([]graph2.Edge, len(b.bridges))
+	for i, k := range b.bridges {
+		bridges[i] = g.Edges[k]
+	}
+	return bridges
+}
+
+// Biconnected returns the biconnected components of an undirected graph.
+//
+// The biconnected components, or blocks, partition the edges of the graph.
+// Two edges are in the same block if they lie on a common simple cycle.
+// A bridge forms a block by itself.  Blocks share articulation points but
+// no edges.
+//
+// Each block is returned as a new Graph.  It has a node for each node of g
+// incident to an edge of the block, with the same key and Data, and the
+// edges of the block with the same edge values.  Nodes with no edges are in
+// no block.  Self loops are ignored.  The order of the result is
+// unspecified.
+func (g Graph) Biconnected() []Graph {
+	return g.blocks(g.biconnect().comps)
+}
+
+// blocks returns a new Graph for each edge list of comps.
+func (g Graph) blocks(comps [][]edgeKey) []Graph {
+	key := make(map[*Node]interface{}, len(g.Nodes))
+	for k, nd := range g.Nodes {
+		key[nd] = k
+	}
+	blocks := make([]Graph, len(comps))
+	for i, c := range comps {
+		b := NewGraph()
+		for _, k := range c {
+			b.Link(key[k.n1], key[k.n2], g.Edges[k])
+		}
+		blocks[i] = b
+	}
+	return blocks
+}
+
+// Block is the key of a block node in a block-cut tree.  The value is the
+// index of the block in the blocks returned with the tree.
+type Block int
+
+// BlockCutTree returns the block-cut tree of an undirected graph.
+//
+// The block-cut tree has a node for each block, or biconnected component,
+// of g and a node for each articulation point of g.  An edge connects each
+// articulation point to each block containing it.  For a connected graph
+// the result is a tree, otherwise it is a forest with a tree for each
+// connected component with at least one edge.
+//
+// Also returned are the blocks, as returned by Biconnected.  Block nodes of
+// the tree are keyed by Block values indexing blocks, with Data the same
+// Block value.  Articulation point nodes have the same key and Data as in
+// g.  Edges of the tree are nil.
+func (g Graph) BlockCutTree() (tree Graph, blocks []Graph) {
+	b := g.biconnect()
+	blocks = g.blocks(b.comps)
+	tree = NewGraph()
+	cut := map[*Node]struct{}{}
+	for _, nd := range b.art {
+		cut[nd] = struct{}{}
+	}
+	for i, bl := range blocks {
+		tree.Nodes[Block(i)] = &Node{Data: Block(i)}
+		for k := range bl.Nodes {
+			nd := g.Nodes[k]
+			if _, ok := cut[nd]; !ok {
+				continue
+			}
+			if _, ok := tree.Nodes[k]; !ok {
+				tree.Nodes[k] = &Node{Data: nd.Data}
+			}
+			tree.Link(Block(i), k, nil)
+		}
+	}
+	return
+}
+
+// edgeKey is the type of keys of Graph.Edges.
+type edgeKey = struct{ n1, n2 *Node }
+
+// findKey returns the key in g.Edges of the edge between n1 and n2.
+func (g Graph) findKey(n1, n2 *Node) edgeKey {
+	k := edgeKey{n1, n2}
+	if _, ok := g.Edges[k]; !ok {
+		k = edgeKey{n2, n1}
+	}
+	return k
+}
+
+// biconnected holds results of Graph.biconnect.
+type biconnected struct {
+	art     []*Node     // articulation points
+	bridges []edgeKey   // bridge edges
+	comps   [][]edgeKey // edges of biconnected components
+}
+
+// biFrame represents a node in the process of being searched.
+type biFrame struct {
+	nd     *Node
+	parent *Node // parent in the search tree, nil for a root
+	i      int   // index in nd.Nbs of next adjacent node to search
+}
+
+// biconnect implements the Hopcroft-Tarjan algorithm, finding articulation
+// points, bridges, and biconnected components with a single depth first
+// search.  It uses an explicit stack rather than recursion.
+func (g Graph) biconnect() (b biconnected) {
+	var (
+		disc  = map[*Node]int{} // discovery order, from 1
+		low   = map[*Node]int{} // lowest disc reachable by a back edge
+		isArt = map[*Node]bool{}
+		call  []biFrame // replaces recursion
+		edges []edgeKey // edges of blocks in progress
+		index = 0
+	)
+	push := func(nd, parent *Node) {
+		index++
+		disc[nd] = index
+		low[nd] = index
+		call = append(call, biFrame{nd: nd, parent: parent})
+	}
+	for _, root := range g.Nodes {
+		if disc[root] > 0 {
+			continue
+		}
+		push(root, nil)
+		rootChildren := 0
+		for len(call) > 0 {
+			f := &call[len(call)-1]
+			if f.i < len(f.nd.Nbs) {
+				nd := f.nd
+				to := nd.Nbs[f.i].To.(*Node)
+				f.i++
+				switch {
+				case to == nd || to == f.parent:
+					// self loop, or the tree edge back to the parent.
+					// (Graph has no parallel edges.)
+				case disc[to] == 0:
+					edges = append(edges, g.findKey(nd, to))
+					if f.parent == nil {
+						rootChildren++
+					}
+					push(to, nd)
+				case disc[to] < disc[nd]:
+					// back edge to an ancestor.
+					edges = append(edges, g.findKey(nd, to))
+					if disc[to] < low[nd] {
+						low[nd] = disc[to]
+					}
+				}
+				continue
+			}
+			// finished with f.nd.
+			nd, p := f.nd, f.parent
+			call = call[:len(call)-1]
+			if p == nil {
+				continue
+			}
+			if low[nd] < low[p] {
+				low[p] = low[nd]
+			}
+			if low[nd] < disc[p] {
+				continue
+			}
+			// p separates the subtree of nd.  edges from the tree edge
+			// p-nd on form a block.
+			if p != root && !isArt[p] {
+				isArt[p] = true
+				b.art = append(b.art, p)
+			}
+			k := g.findKey(p, nd)
+			if low[nd] > disc[p] {
+				b.bridges = append(b.bridges, k)
+			}
+			j := len(edges) - 1
+			for edges[j] != k {
+				j--
+			}
+			b.comps = append(b.comps, append([]edgeKey{}, edges[j:]...))
+			edges = edges[:j]
+		}
+		if rootChildren > 1 {
+			b.art = append(b.art, root)
+		}
+	}
+	return
+}
@@ -0,0 +1,198 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph2/adj"
+)
+
+// biGraph is two triangles joined by a path, with a pendant node.
+//
+//	0-1-2-0  2-3  3-4-5-3  5-6
+func biGraph() adj.Graph {
+	g := adj.NewGraph()
+	g.Link(0, 1, "a")
+	g.Link(1, 2, "b")
+	g.Link(2, 0, "c")
+	g.Link(2, 3, "d")
+	g.Link(3, 4, "e")
+	g.Link(4, 5, "f")
+	g.Link(5, 3, "g")
+	g.Link(5, 6, "h")
+	return g
+}
+
+func ExampleGraph_ArticulationPoints() {
+	var s []int
+	for _, nd := range biGraph().ArticulationPoints() {
+		s = append(s, nd.Data.(int))
+	}
+	sort.Ints(s)
+	fmt.Println(s)
+	// Output:
+	// [2 3 5]
+}
+
+func ExampleGraph_Bridges() {
+	var s []string
+	for _, ed := range biGraph().Bridges() {
+		s = append(s, ed.(string))
+	}
+	sort.Strings(s)
+	fmt.Println(s)
+	// Output:
+	// [d h]
+}
+
+func ExampleGraph_Biconnected() {
+	var s []string
+	for _, b := range biGraph().Biconnected() {
+		var es []string
+		for _, ed := range b.Edges {
+			es = append(es, ed.(string))
+		}
+		sort.Strings(es)
+		s = append(s, fmt.Sprint(es))
+	}
+	sort.Strings(s)
+	for _, s := range s {
+		fmt.Println(s)
+	}
+	// Output:
+	// [a b c]
+	// [d]
+	// [e f g]
+	// [h]
+}
+
+func ExampleGraph_BlockCutTree() {
+	tree, blocks := biGraph().BlockCutTree()
+	var s []string
+	for k, nd := range tree.Nodes {
+		b, ok := k.(adj.Block)
+		if !ok {
+			continue
+		}
+		var cuts []int
+		for _, h := range nd.Nbs {
+			cuts = append(cuts, h.To.(*adj.Node).Data.(int))
+		}
+		sort.Ints(cuts)
+		s = append(s, fmt.Sprint(len(blocks[b].Edges), " edges, cut ", cuts))
+	}
+	sort.Strings(s)
+	for _, s := range s {
+		fmt.Println(s)
+	}
+	fmt.Println(len(tree.Nodes), "nodes", len(tree.Edges), "edges")
+	// Output:
+	// 1 edges, cut [2 3]
+	// 1 edges, cut [5]
+	// 3 edges, cut [2]
+	// 3 edges, cut [3 5]
+	// 7 nodes 6 edges
+}
+
+// numComponents counts connected components of g with node skip and the
+// edge with value skipEd removed.
+func numComponents(g adj.Graph, skip *adj.Node, skipEd interface{}) int {
+	seen := map[*adj.Node]bool{skip: true}
+	n := 0
+	for _, root := range g.Nodes {
+		if seen[root] {
+			continue
+		}
+		n++
+		seen[root] = true
+		stack := []*adj.Node{root}
+		for len(stack) > 0 {
+			nd := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, h := range nd.Nbs {
+				to := h.To.(*adj.Node)
+				if h.Ed == skipEd || seen[to] {
+					continue
+				}
+				seen[to] = true
+				stack = append(stack, to)
+			}
+		}
+	}
+	return n
+}
+
+func TestBiconnectedRandom(t *testing.T) {
+	x := rand.New(rand.NewSource(11))
+	for trial := 0; trial < 20; trial++ {
+		g := adj.NewGraph()
+		nn := 10 + x.Intn(40)
+		for i := 0; i < nn; i++ {
+			g.Nodes[i] = &adj.Node{Data: i}
+		}
+		for i, ne := 0, x.Intn(2*nn); i < ne; i++ {
+			g.Link(x.Intn(nn), x.Intn(nn), i) // edge values are unique
+		}
+		c0 := numComponents(g, nil, nil)
+		art := map[*adj.Node]bool{}
+		for _, nd := range g.ArticulationPoints() {
+			if art[nd] {
+				t.Fatal("articulation point repeated", nd)
+			}
+			art[nd] = true
+		}
+		for _, nd := range g.Nodes {
+			// removing nd removes its own component if it is isolated
+			isolated := true
+			for _, h := range nd.Nbs {
+				if h.To != nd {
+					isolated = false
+				}
+			}
+			want := !isolated && numComponents(g, nd, nil) > c0
+			if art[nd] != want {
+				t.Fatal("node", nd, "articulation", art[nd], "want", want)
+			}
+		}
+		bridge := map[interface{}]bool{}
+		for _, ed := range g.Bridges() {
+			bridge[ed] = true
+		}
+		for _, ed := range g.Edges {
+			want := numComponents(g, nil, ed) > c0
+			if bridge[ed] != want {
+				t.Fatal("edge", ed, "bridge", bridge[ed], "want", want)
+			}
+		}
+		// blocks partition edges other than self loops
+		inBlock := map[interface{}]int{}
+		for _, b := range g.Biconnected() {
+			if len(b.Edges) > 1 && len(b.ArticulationPoints()) > 0 {
+				t.Fatal("block not biconnected")
+			}
+			if len(b.Edges) == 1 {
+				for _, ed := range b.Edges {
+					if !bridge[ed] {
+						t.Fatal("single edge block", ed, "not a bridge")
+					}
+				}
+			}
+			for _, ed := range b.Edges {
+				inBlock[ed]++
+			}
+		}
+		for _, nd := range g.Nodes {
+			for _, h := range nd.Nbs {
+				n := inBlock[h.Ed]
+				if h.To == nd && n != 0 || h.To != nd && n != 1 {
+					t.Fatal("edge", h.Ed, "in", n, "blocks")
+				}
+			}
+		}
+	}
+}
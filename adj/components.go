@@ -0,0 +1,284 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"github.com/soniakeys/graph2"
+)
+
+// DisjointSet is a disjoint set forest, or union-find structure, over
+// nodes.
+//
+// It uses union by size and path compression so that Find and Union run in
+// nearly constant amortized time.  The zero value is an empty DisjointSet
+// ready to use.  Nodes are added as they are first passed to Add, Find, or
+// Union.
+type DisjointSet struct {
+	parent map[graph2.Node]graph2.Node
+	size   map[graph2.Node]int // sizes of sets, by representative
+	sets   int                 // number of sets
+}
+
+// Add adds node n as a set by itself, if n is not already in the structure.
+func (s *DisjointSet) Add(n graph2.Node) {
+	if _, ok := s.parent[n]; ok {
+		return
+	}
+	if s.parent == nil {
+		s.parent = map[graph2.Node]graph2.Node{}
+		s.size = map[graph2.Node]int{}
+	}
+	s.parent[n] = n
+	s.size[n] = 1
+	s.sets++
+}
+
+// Find returns the representative node of the set containing n.
+func (s *DisjointSet) Find(n graph2.Node) graph2.Node {
+	s.Add(n)
+	root := n
+	for p := s.parent[root]; p != root; p = s.parent[root] {
+		root = p
+	}
+	// compress path
+	for n != root {
+		p := s.parent[n]
+		s.parent[n] = root
+		n = p
+	}
+	return root
+}
+
+// Union merges the sets containing n1 and n2.  It returns false if they
+// were already the same set.
+func (s *DisjointSet) Union(n1, n2 graph2.Node) bool {
+	r1, r2 := s.Find(n1), s.Find(n2)
+	if r1 == r2 {
+		return false
+	}
+	if s.size[r1] > s.size[r2] {
+		r1, r2 = r2, r1
+	}
+	s.parent[r1] = r2
+	s.size[r2] += s.size[r1]
+	delete(s.size, r1)
+	s.sets--
+	return true
+}
+
+// Connected returns true if n1 and n2 are in the same set.
+func (s *DisjointSet) Connected(n1, n2 graph2.Node) bool {
+	return s.Find(n1) == s.Find(n2)
+}
+
+// Len returns the number of nodes in the structure.
+func (s *DisjointSet) Len() int { return len(s.parent) }
+
+// Sets returns the number of disjoint sets.
+func (s *DisjointSet) Sets() int { return s.sets }
+
+// SetOf returns the number of nodes in the set containing n.
+func (s *DisjointSet) SetOf(n graph2.Node) int { return s.size[s.Find(n)] }
+
+// Components returns the connected components of an undirected graph.
+//
+// Each component is returned as a list of nodes.  The order of components
+// and of nodes within components is unspecified.
+func (g Graph) Components() [][]*Node {
+	return newComponentSet(g.Nodes).partition(g.Nodes)
+}
+
+// WeakComponents returns the weakly connected components of a directed
+// graph, that is, the connected components when arc directions are
+// ignored.
+//
+// Each component is returned as a list of nodes.  The order of components
+// and of nodes within components is unspecified.
+func (g Digraph) WeakComponents() [][]*Node {
+	return newComponentSet(g).partition(g)
+}
+
+// Connected returns true if the nodes with keys n1 and n2 are in the same
+// connected component of g.  It searches g and returns false if either key
+// is not in g.  See TrackedGraph for nearly constant time queries.
+func (g Graph) Connected(n1, n2 interface{}) bool {
+	nd1, ok1 := g.Nodes[n1]
+	nd2, ok2 := g.Nodes[n2]
+	if !ok1 || !ok2 {
+		return false
+	}
+	if nd1 == nd2 {
+		return true
+	}
+	seen := map[*Node]struct{}{nd1: {}}
+	stack := []*Node{nd1}
+	for len(stack) > 0 {
+		nd := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, h := range nd.Nbs {
+			to := h.To.(*Node)
+			if to == nd2 {
+				return true
+			}
+			if _, ok := seen[to]; !ok {
+				seen[to] = struct{}{}
+				stack = append(stack, to)
+			}
+		}
+	}
+	return false
+}
+
+// TrackedGraph is an undirected graph that maintains its connected
+// components as edges are added, so that Connected runs in nearly constant
+// time.
+//
+// Edges and nodes must be added and removed with the methods of
+// TrackedGraph rather than those of the embedded Graph, or components will
+// be out of date.
+type TrackedGraph struct {
+	Graph
+	ds DisjointSet
+}
+
+// TrackComponents returns a TrackedGraph maintaining connected components
+// of g.  The TrackedGraph shares the maps of g; they are not copied.
+func TrackComponents(g Graph) *TrackedGraph {
+	return &TrackedGraph{g, *newComponentSet(g.Nodes)}
+}
+
+// Link adds an edge as documented for Graph.Link and updates components.
+func (t *TrackedGraph) Link(n1, n2 interface{}, ed graph2.Edge) {
+	t.Graph.Link(n1, n2, ed)
+	t.ds.Union(t.Nodes[n1], t.Nodes[n2])
+}
+
+// Unlink removes an edge as documented for Graph.Unlink and updates
+// components.
+//
+// Components cannot be split incrementally.  Unlink searches the component
+// that held the edge, taking time proportional to its size.
+func (t *TrackedGraph) Unlink(n1, n2 interface{}) bool {
+	if !t.Graph.Unlink(n1, n2) {
+		return false
+	}
+	t.split(nil, []*Node{t.Nodes[n1], t.Nodes[n2]})
+	return true
+}
+
+// RemoveNode removes a node as documented for Graph.RemoveNode and updates
+// components.  As with Unlink, it searches the component that held the
+// node.
+func (t *TrackedGraph) RemoveNode(n interface{}) bool {
+	nd, ok := t.Nodes[n]
+	if !ok || !t.Graph.RemoveNode(n) {
+		return false
+	}
+	// Graph.RemoveNode leaves the Nbs of the removed node itself
+	nbs := make([]*Node, len(nd.Nbs))
+	for i, h := range nd.Nbs {
+		nbs[i] = h.To.(*Node)
+	}
+	t.split(nd, nbs)
+	return true
+}
+
+// split updates components after edges are removed between nodes of nds,
+// or after node gone is removed, nds then being its former neighbors.
+// All were in a single component.  It searches from each node of nds and
+// rebuilds sets for just that component.
+func (t *TrackedGraph) split(gone *Node, nds []*Node) {
+	seen := map[*Node]struct{}{}
+	if gone != nil {
+		seen[gone] = struct{}{} // in case of a self loop
+	}
+	var parts [][]*Node
+	for _, nd := range nds {
+		if _, ok := seen[nd]; ok {
+			continue
+		}
+		seen[nd] = struct{}{}
+		part := []*Node{nd}
+		for i := 0; i < len(part); i++ {
+			for _, h := range part[i].Nbs {
+				to := h.To.(*Node)
+				if _, ok := seen[to]; !ok {
+					seen[to] = struct{}{}
+					part = append(part, to)
+				}
+			}
+		}
+		parts = append(parts, part)
+	}
+	if gone == nil && len(parts) < 2 {
+		return // still connected
+	}
+	for _, part := range parts {
+		t.ds.drop(part)
+	}
+	if gone != nil {
+		t.ds.drop([]*Node{gone})
+	}
+	for _, part := range parts {
+		for _, nd := range part {
+			t.ds.Union(part[0], nd)
+		}
+	}
+}
+
+// Components returns the connected components of t as documented for
+// Graph.Components.
+func (t *TrackedGraph) Components() [][]*Node {
+	return t.ds.partition(t.Nodes)
+}
+
+// Connected returns true if the nodes with keys n1 and n2 are in the same
+// connected component.  It returns false if either key is not in t.
+func (t *TrackedGraph) Connected(n1, n2 interface{}) bool {
+	nd1, ok1 := t.Nodes[n1]
+	nd2, ok2 := t.Nodes[n2]
+	return ok1 && ok2 && t.ds.Connected(nd1, nd2)
+}
+
+// newComponentSet returns a DisjointSet of the nodes of m with sets of
+// connected components, ignoring arc directions.
+func newComponentSet(m map[interface{}]*Node) *DisjointSet {
+	ds := &DisjointSet{}
+	for _, nd := range m {
+		ds.Add(nd)
+		for _, h := range nd.Nbs {
+			ds.Union(nd, h.To.(*Node))
+		}
+	}
+	return ds
+}
+
+// drop removes nodes ns from s.  Nodes of a set must all be dropped before
+// s is used again.
+func (s *DisjointSet) drop(ns []*Node) {
+	for _, n := range ns {
+		if _, ok := s.size[n]; ok {
+			delete(s.size, n)
+			s.sets--
+		}
+		delete(s.parent, n)
+	}
+}
+
+// partition lists nodes of m by set of s.
+func (s *DisjointSet) partition(m map[interface{}]*Node) [][]*Node {
+	x := map[graph2.Node]int{} // index in result, by representative
+	var p [][]*Node
+	for _, nd := range m {
+		r := s.Find(nd)
+		i, ok := x[r]
+		if !ok {
+			i = len(p)
+			x[r] = i
+			p = append(p, nil)
+		}
+		p[i] = append(p[i], nd)
+	}
+	return p
+}
@@ -0,0 +1,114 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph2/adj"
+)
+
+// sortedComponents formats components for repeatable output.
+func sortedComponents(comps [][]*adj.Node) [][]int {
+	r := make([][]int, len(comps))
+	for i, c := range comps {
+		for _, nd := range c {
+			r[i] = append(r[i], nd.Data.(int))
+		}
+		sort.Ints(r[i])
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i][0] < r[j][0] })
+	return r
+}
+
+func ExampleGraph_Components() {
+	g := adj.NewGraph()
+	g.Link(0, 1, nil)
+	g.Link(1, 2, nil)
+	g.Link(3, 4, nil)
+	g.Nodes[5] = &adj.Node{Data: 5}
+	fmt.Println(sortedComponents(g.Components()))
+	// Output:
+	// [[0 1 2] [3 4] [5]]
+}
+
+func ExampleDigraph_WeakComponents() {
+	g := adj.Digraph{}
+	g.Link(0, 1, nil)
+	g.Link(2, 1, nil)
+	g.Link(3, 4, nil)
+	fmt.Println(sortedComponents(g.WeakComponents()))
+	// Output:
+	// [[0 1 2] [3 4]]
+}
+
+func ExampleTrackComponents() {
+	g := adj.TrackComponents(adj.NewGraph())
+	g.Link(0, 1, nil)
+	g.Link(2, 3, nil)
+	fmt.Println(g.Connected(0, 3))
+	g.Link(1, 2, nil)
+	fmt.Println(g.Connected(0, 3))
+	// Output:
+	// false
+	// true
+}
+
+func ExampleDisjointSet() {
+	g := adj.Digraph{}
+	g.Link(0, 1, nil)
+	g.Link(2, 3, nil)
+	var s adj.DisjointSet
+	for _, nd := range g {
+		s.Add(nd)
+	}
+	fmt.Println(s.Len(), "nodes", s.Sets(), "sets")
+	s.Union(g[0], g[2])
+	fmt.Println(s.Connected(g[0], g[2]), s.Connected(g[0], g[1]))
+	fmt.Println(s.Sets(), "sets", s.SetOf(g[2]), "in set of 2")
+	// Output:
+	// 4 nodes 4 sets
+	// true false
+	// 3 sets 2 in set of 2
+}
+
+func TestComponentsRandom(t *testing.T) {
+	x := rand.New(rand.NewSource(13))
+	tracked := adj.TrackComponents(adj.NewGraph())
+	plain := adj.NewGraph()
+	const nn = 300
+	for i := 0; i < nn; i++ {
+		tracked.Link(i, i, nil) // self loops put all nodes in the graphs
+		plain.Link(i, i, nil)
+	}
+	for i := 0; i < 400; i++ {
+		n1, n2 := x.Intn(nn), x.Intn(nn)
+		tracked.Link(n1, n2, nil)
+		plain.Link(n1, n2, nil)
+		if i%50 != 0 {
+			continue
+		}
+		ct := tracked.Components()
+		cp := plain.Components()
+		if fmt.Sprint(sortedComponents(ct)) != fmt.Sprint(sortedComponents(cp)) {
+			t.Fatal("tracked and searched components differ")
+		}
+		comp := map[int]int{}
+		for i, c := range ct {
+			for _, nd := range c {
+				comp[nd.Data.(int)] = i
+			}
+		}
+		for j := 0; j < 100; j++ {
+			a, b := x.Intn(nn), x.Intn(nn)
+			want := comp[a] == comp[b]
+			if tracked.Connected(a, b) != want || plain.Connected(a, b) != want {
+				t.Fatal("Connected", a, b, "want", want)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import "github.com/soniakeys/graph2"
+
+// Condensation builds the condensation of a directed graph.
+//
+// Argument comps must hold the strongly connected components of the graph,
+// as returned by search.StronglyConnected for example.  Each component
+// becomes a node of the result.  The node is keyed by the index of the
+// component in comps, and the index is also the Data of the node.  An arc
+// links two nodes of the result if any node of the first component has an
+// arc to a node of the second.  Arcs are nil and at most one arc links any
+// pair of components.  Arcs within a component are not represented and
+// arcs to nodes not in any component are ignored.
+//
+// The condensation of any directed graph is a directed acyclic graph.
+func Condensation(comps [][]graph2.Node) Digraph {
+	g := make(Digraph, len(comps))
+	cx := map[graph2.Node]int{}
+	for i, c := range comps {
+		g[i] = &Node{Data: i}
+		for _, nd := range c {
+			cx[nd] = i
+		}
+	}
+	linked := map[[2]int]struct{}{}
+	for i, c := range comps {
+		for _, nd := range c {
+			nd.VisitAdjNodes(func(nb graph2.Node) bool {
+				j, ok := cx[nb]
+				if !ok || j == i {
+					return true
+				}
+				if _, ok := linked[[2]int{i, j}]; !ok {
+					linked[[2]int{i, j}] = struct{}{}
+					g.Link(i, j, nil)
+				}
+				return true
+			})
+		}
+	}
+	return g
+}
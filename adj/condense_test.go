@@ -0,0 +1,42 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/adj"
+	"github.com/soniakeys/graph2/search"
+)
+
+func ExampleCondensation() {
+	// a build graph where an arc means "depends on"
+	g := adj.Digraph{}
+	g.Link("app", "lib", nil)
+	g.Link("lib", "util", nil)
+	g.Link("util", "log", nil)
+	g.Link("log", "util", nil) // a dependency cycle
+	g.Link("app", "log", nil)
+	nodes := []graph2.Node{g["app"], g["lib"], g["util"], g["log"]}
+	comps := search.StronglyConnected(nodes)
+	for i, c := range comps {
+		if len(c) > 1 {
+			fmt.Println("cycle:", i, c)
+		}
+	}
+	c := adj.Condensation(comps)
+	for i := range comps {
+		fmt.Print(i, " depends on")
+		c[i].VisitAdjHalfs(func(h graph2.Half) {
+			fmt.Print(" ", h.To)
+		})
+		fmt.Println()
+	}
+	// Output:
+	// cycle: 0 [log util]
+	// 0 depends on
+	// 1 depends on 0
+	// 2 depends on 1 0
+}
@@ -0,0 +1,165 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/soniakeys/graph2"
+)
+
+// ReadDIMACS reads a directed graph in the .gr shortest path format of the
+// 9th DIMACS Implementation Challenge.
+//
+// Lines are "c" comments, a single problem line "p sp n m", and arc lines
+// "a u v w" for an arc from node u to node v with weight w.  Nodes are
+// numbered 1 through n.  Input is read as a stream.
+//
+// Nodes are keyed by their integer IDs.  Keys and Data are ints.  All n
+// nodes are present in the result, including nodes without arcs.  Arcs are
+// adj.Weighted.
+func ReadDIMACS(r io.Reader) (Digraph, error) {
+	var g Digraph
+	var nodes []*Node // nodes by ID
+	m := 0
+	err := scanLines(r, func(f [][]byte) error {
+		switch string(f[0]) {
+		case "c":
+			return nil
+		case "p":
+			if nodes != nil {
+				return errors.New("multiple problem lines")
+			}
+			if len(f) != 4 || string(f[1]) != "sp" {
+				return errors.New(`expected "p sp n m"`)
+			}
+			n, err := atoi(f[2])
+			if err != nil {
+				return err
+			}
+			if m, err = atoi(f[3]); err != nil {
+				return err
+			}
+			if n < 0 || m < 0 {
+				return errors.New("negative size")
+			}
+			g = make(Digraph, n)
+			nodes = make([]*Node, n+1)
+			for id := 1; id <= n; id++ {
+				nd := &Node{Data: id}
+				g[id] = nd
+				nodes[id] = nd
+			}
+			return nil
+		case "a":
+			if nodes == nil {
+				return errors.New("arc before problem line")
+			}
+			if len(f) != 4 {
+				return errors.New(`expected "a u v w"`)
+			}
+			u, err := atoi(f[1])
+			if err != nil {
+				return err
+			}
+			v, err := atoi(f[2])
+			if err != nil {
+				return err
+			}
+			if u < 1 || u >= len(nodes) || v < 1 || v >= len(nodes) {
+				return errors.New("node ID out of range")
+			}
+			w, err := strconv.ParseFloat(string(f[3]), 64)
+			if err != nil {
+				return err
+			}
+			link(nodes[u], nodes[v], Weighted(w))
+			m--
+			return nil
+		}
+		return fmt.Errorf("unknown line type %q", f[0])
+	})
+	if err == nil && nodes == nil {
+		err = errors.New("no problem line")
+	}
+	if err == nil && m != 0 {
+		err = errors.New("number of arcs does not match problem line")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DIMACS %v", err)
+	}
+	return g, nil
+}
+
+// Coord is node Data holding coordinates of a node, as read by
+// ReadDIMACSCoords.  It implements graph2.Estimator.
+type Coord struct {
+	ID    int     // node ID, the node key
+	X, Y  float64 // coordinates
+	Scale float64 // factor converting coordinate distance to arc weight
+}
+
+// Estimate returns the Euclidean distance from c to the coordinates of e,
+// multiplied by c.Scale.  The Data of e must be a *Coord.
+//
+// For the estimate to be admissible, Scale must be small enough that no
+// path is shorter than the scaled distance between its end points.
+func (c *Coord) Estimate(e graph2.EstimateNode) float64 {
+	c2 := e.(*Node).Data.(*Coord)
+	return c.Scale * math.Hypot(c2.X-c.X, c2.Y-c.Y)
+}
+
+// String returns the node ID.
+func (c *Coord) String() string { return strconv.Itoa(c.ID) }
+
+// ReadDIMACSCoords reads node coordinates in the .co format of the 9th
+// DIMACS Implementation Challenge and sets the Data of nodes of g to *Coord
+// values, each with the given scale.
+//
+// Lines are "c" comments, a problem line "p aux sp co n", and coordinate
+// lines "v id x y".  Input is read as a stream.  Node keys are unchanged.
+// ReadDIMACSCoords returns an error if an ID is not a node of g.  Nodes
+// without coordinate lines keep their Data.
+//
+// With coordinates, nodes of g can be searched with search.AStarA and
+// search.AStarM.
+func ReadDIMACSCoords(g Digraph, r io.Reader, scale float64) error {
+	err := scanLines(r, func(f [][]byte) error {
+		switch string(f[0]) {
+		case "c", "p":
+			return nil
+		case "v":
+			if len(f) != 4 {
+				return errors.New(`expected "v id x y"`)
+			}
+			id, err := atoi(f[1])
+			if err != nil {
+				return err
+			}
+			nd, ok := g[id]
+			if !ok {
+				return fmt.Errorf("node %d not in graph", id)
+			}
+			x, err := strconv.ParseFloat(string(f[2]), 64)
+			if err != nil {
+				return err
+			}
+			y, err := strconv.ParseFloat(string(f[3]), 64)
+			if err != nil {
+				return err
+			}
+			nd.Data = &Coord{id, x, y, scale}
+			return nil
+		}
+		return fmt.Errorf("unknown line type %q", f[0])
+	})
+	if err != nil {
+		return fmt.Errorf("DIMACS coordinates %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph2/adj"
+	"github.com/soniakeys/graph2/search"
+)
+
+const dimacsGr = `c 9th DIMACS challenge format
+p sp 5 7
+a 1 2 4
+a 1 3 2
+a 2 4 5
+a 3 2 1
+a 3 4 8
+a 4 5 3
+a 5 1 7
+`
+
+const dimacsCo = `c coordinates
+p aux sp co 5
+v 1 0 0
+v 2 3 0
+v 3 2 0
+v 4 6 0
+v 5 9 0
+`
+
+func ExampleReadDIMACSCoords() {
+	g, err := adj.ReadDIMACS(strings.NewReader(dimacsGr))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := adj.ReadDIMACSCoords(g, strings.NewReader(dimacsCo), 1); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(search.AStarM(g[1], g[5]))
+	// Output:
+	// [{<nil> 1} {2 3} {1 2} {5 4} {3 5}] 11
+}
+
+func TestReadDIMACS(t *testing.T) {
+	g, err := adj.ReadDIMACS(strings.NewReader(dimacsGr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReadDigraph(t, g)
+	if len(g) != 5 || g.NumEdges() != 7 {
+		t.Fatalf("got %d nodes, %d arcs", len(g), g.NumEdges())
+	}
+	_, d := search.DijkstraShortestPath(g[1], g[5])
+	if err := adj.ReadDIMACSCoords(g, strings.NewReader(dimacsCo), 1); err != nil {
+		t.Fatal(err)
+	}
+	checkReadDigraph(t, g)
+	if c := g[4].Data.(*adj.Coord); *c != (adj.Coord{4, 6, 0, 1}) {
+		t.Fatalf("node 4 Data %v", *c)
+	}
+	if _, a := search.AStarA(g[1], g[5]); a != d {
+		t.Fatalf("A* distance %g, Dijkstra %g", a, d)
+	}
+	for _, s := range []string{
+		"a 1 2 3\np sp 2 1\n",
+		"p sp 2 1\np sp 2 1\n",
+		"p sp 2 1\na 1 3 1\n",
+		"p sp 2 1\na 1 2 x\n",
+		"p sp 2 2\na 1 2 1\n",
+		"p sp 2 1\nx\n",
+		"c nothing\n",
+	} {
+		if _, err := adj.ReadDIMACS(strings.NewReader(s)); err == nil {
+			t.Fatalf("no error reading %q", s)
+		}
+	}
+	if err := adj.ReadDIMACSCoords(g, strings.NewReader("v 9 0 0\n"), 1); err == nil {
+		t.Fatal("no error for coordinates of missing node")
+	}
+}
+
+func TestReadEdgeList(t *testing.T) {
+	g, err := adj.ReadEdgeList(strings.NewReader(`# SNAP style
+# FromNodeId	ToNodeId
+0	1
+1	2 2.5
+% another comment
+
+2 0
+2	2
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReadDigraph(t, g)
+	if len(g) != 3 || g.NumEdges() != 4 {
+		t.Fatalf("got %d nodes, %d arcs", len(g), g.NumEdges())
+	}
+	if _, d := search.DijkstraShortestPath(g[0], g[2]); d != 3.5 {
+		t.Fatalf("distance %g", d)
+	}
+	for _, s := range []string{"1\n", "1 2 3 4\n", "a 1\n", "1 2 x\n"} {
+		if _, err := adj.ReadEdgeList(strings.NewReader(s)); err == nil {
+			t.Fatalf("no error reading %q", s)
+		}
+	}
+}
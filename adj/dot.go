@@ -0,0 +1,569 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/internal"
+)
+
+// DOTConfig holds options for writing DOT.  A nil *DOTConfig gives
+// defaults.
+type DOTConfig struct {
+	// Name is the graph name.  If empty, no name is written.
+	Name string
+	// Path is a path to highlight, as returned by functions of package
+	// graph/search.  Nodes and arcs of the path are drawn in red.
+	Path []graph2.Half
+}
+
+// WriteDOT writes g in the Graphviz DOT language.
+//
+// Nodes are labeled with Node.String.  Arcs are labeled with their weights
+// if they implement graph2.Weighted, otherwise with a string representation
+// of the arc value.  Nil arcs are not labeled.  Nodes are written in order
+// of their labels, then of their keys, so that output is repeatable.
+func (g Digraph) WriteDOT(w io.Writer, c *DOTConfig) error {
+	return writeDOT(w, c, "digraph", "->", g, func(nd *Node, f func(to *Node, ed interface{})) {
+		for _, h := range nd.Nbs {
+			f(h.To.(*Node), h.Ed)
+		}
+	})
+}
+
+// WriteDOT writes g in the Graphviz DOT language.
+//
+// Each edge is written once.  Labels are as documented for
+// Digraph.WriteDOT.
+func (g Graph) WriteDOT(w io.Writer, c *DOTConfig) error {
+	out := map[*Node][]graph2.Half{}
+	for k, ed := range g.Edges {
+		out[k.n1] = append(out[k.n1], graph2.Half{ed, k.n2})
+	}
+	return writeDOT(w, c, "graph", "--", g.Nodes, func(nd *Node, f func(to *Node, ed interface{})) {
+		for _, h := range out[nd] {
+			f(h.To.(*Node), h.Ed)
+		}
+	})
+}
+
+// writeDOT writes nodes m, with arcs or edges from each node as visited by
+// function arcs.
+func writeDOT(w io.Writer, c *DOTConfig, kind, op string, m map[interface{}]*Node, arcs func(*Node, func(*Node, interface{}))) error {
+	if c == nil {
+		c = &DOTConfig{}
+	}
+	nodes := sortedNodes(m)
+	id := make(map[*Node]int, len(nodes))
+	for i, nd := range nodes {
+		id[nd] = i
+	}
+	// path nodes and arcs to highlight
+	onPath := map[*Node]bool{}
+	type pathArc struct{ from, to *Node }
+	pathArcs := map[pathArc][]interface{}{}
+	for i, h := range c.Path {
+		to, ok := h.To.(*Node)
+		if !ok {
+			continue
+		}
+		onPath[to] = true
+		if i > 0 {
+			if from, ok := c.Path[i-1].To.(*Node); ok {
+				a := pathArc{from, to}
+				pathArcs[a] = append(pathArcs[a], h.Ed)
+				if kind == "graph" {
+					a = pathArc{to, from}
+					pathArcs[a] = append(pathArcs[a], h.Ed)
+				}
+			}
+		}
+	}
+	b := bufio.NewWriter(w)
+	b.WriteString(kind)
+	if c.Name != "" {
+		b.WriteString(" " + dotQuote(c.Name))
+	}
+	b.WriteString(" {\n")
+	for i, nd := range nodes {
+		fmt.Fprintf(b, "\tn%d [label=%s", i, dotQuote(nd.String()))
+		if onPath[nd] {
+			b.WriteString(" color=red")
+		}
+		b.WriteString("];\n")
+	}
+	type arc struct {
+		to *Node
+		ed interface{}
+	}
+	var out []arc
+	for i, nd := range nodes {
+		out = out[:0]
+		arcs(nd, func(to *Node, ed interface{}) { out = append(out, arc{to, ed}) })
+		if kind == "graph" {
+			// edges of a Graph come from a map.  sort for repeatability.
+			sort.Slice(out, func(i, j int) bool { return id[out[i].to] < id[out[j].to] })
+		}
+		for _, a := range out {
+			to, ed := a.to, a.ed
+			fmt.Fprintf(b, "\tn%d %s n%d", i, op, id[to])
+			var attr []string
+			switch e := ed.(type) {
+			case nil:
+			case graph2.Weighted:
+				attr = append(attr, "label="+dotQuote(
+					strconv.FormatFloat(e.Weight(), 'g', -1, 64)))
+			default:
+				attr = append(attr, "label="+dotQuote(fmt.Sprint(ed)))
+			}
+			for _, pe := range pathArcs[pathArc{nd, to}] {
+				if internal.Equal(pe, ed) {
+					attr = append(attr, "color=red", "penwidth=2")
+					break
+				}
+			}
+			if len(attr) > 0 {
+				b.WriteString(" [" + strings.Join(attr, " ") + "]")
+			}
+			b.WriteString(";\n")
+		}
+	}
+	b.WriteString("}\n")
+	return b.Flush()
+}
+
+// sortedNodes returns the nodes of m in order of their labels, that is
+// of Node.String.  Nodes with equal labels are ordered by their keys in m,
+// compared by Go syntax representation.
+func sortedNodes(m map[interface{}]*Node) []*Node {
+	type sortNode struct {
+		label, key string
+		nd         *Node
+	}
+	s := make([]sortNode, 0, len(m))
+	for k, nd := range m {
+		s = append(s, sortNode{nd.String(), fmt.Sprintf("%#v", k), nd})
+	}
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].label != s[j].label {
+			return s[i].label < s[j].label
+		}
+		return s[i].key < s[j].key
+	})
+	nodes := make([]*Node, len(s))
+	for i, sn := range s {
+		nodes[i] = sn.nd
+	}
+	return nodes
+}
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// ReadDOTDigraph reads a directed graph in the Graphviz DOT language.
+//
+// A subset of DOT is supported.  Node and edge statements are read,
+// including chains of edges such as "a -> b -> c".  Graph, node, and edge
+// attribute statements and graph attribute assignments are accepted but
+// ignored.  Subgraphs and ports are not supported and give an error, as
+// does other unexpected punctuation.  Comments are allowed.
+//
+// Nodes are keyed by their DOT IDs.  Node Data is the label attribute if
+// present, otherwise the ID, so that distinct nodes with the same label
+// remain distinct.  Keys and Data are strings.  An edge with a weight or label
+// attribute that parses as a number has an adj.Weighted value.  Otherwise
+// an edge with a label has the label string as its value and an edge with
+// no label has a nil value.
+//
+// Output of Digraph.WriteDOT can be read back.  Node keys are then the
+// IDs written, such as "n0", and node Data the labels written.
+func ReadDOTDigraph(r io.Reader) (Digraph, error) {
+	p, err := parseDOT(r)
+	if err != nil {
+		return nil, err
+	}
+	if !p.directed {
+		return nil, errors.New("DOT: not a digraph")
+	}
+	g := Digraph{}
+	p.build(func(k, data interface{}) {
+		g[k] = &Node{Data: data}
+	}, func(n1, n2 interface{}, ed interface{}) { g.Link(n1, n2, ed) })
+	return g, nil
+}
+
+// ReadDOTGraph reads an undirected graph in the Graphviz DOT language.
+//
+// The DOT subset and the values of nodes and edges are as documented for
+// ReadDOTDigraph.  Edges are added with Graph.Link, which does not add
+// parallel edges.  Of parallel edges in the DOT, only the first is kept.
+func ReadDOTGraph(r io.Reader) (Graph, error) {
+	p, err := parseDOT(r)
+	if err != nil {
+		return Graph{}, err
+	}
+	if p.directed {
+		return Graph{}, errors.New("DOT: not an undirected graph")
+	}
+	g := NewGraph()
+	p.build(func(k, data interface{}) {
+		g.Nodes[k] = &Node{Data: data}
+	}, func(n1, n2 interface{}, ed interface{}) { g.Link(n1, n2, ed) })
+	return g, nil
+}
+
+// dotGraph is a parsed DOT graph.
+type dotGraph struct {
+	directed bool
+	nodes    []string                     // IDs in order of appearance
+	attr     map[string]map[string]string // node attributes, by ID
+	edges    []dotEdge
+}
+
+type dotEdge struct {
+	from, to string
+	attr     map[string]string
+}
+
+// build adds nodes and edges of p to a graph with functions add and link.
+// Add is called once for each node ID, before any edges are linked.
+func (p *dotGraph) build(add func(k, data interface{}), link func(n1, n2 interface{}, ed interface{})) {
+	for _, id := range p.nodes {
+		if l, ok := p.attr[id]["label"]; ok {
+			add(id, l)
+		} else {
+			add(id, id)
+		}
+	}
+	for _, e := range p.edges {
+		var ed interface{}
+		if w, ok := e.attr["weight"]; ok {
+			if f, err := strconv.ParseFloat(w, 64); err == nil {
+				ed = Weighted(f)
+			}
+		}
+		if l, ok := e.attr["label"]; ok && ed == nil {
+			if f, err := strconv.ParseFloat(l, 64); err == nil {
+				ed = Weighted(f)
+			} else {
+				ed = l
+			}
+		}
+		link(e.from, e.to, ed)
+	}
+}
+
+// dotParser holds the state of parsing.
+type dotParser struct {
+	s   []rune
+	pos int
+	g   *dotGraph
+}
+
+func parseDOT(r io.Reader) (*dotGraph, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &dotParser{
+		s: []rune(string(b)),
+		g: &dotGraph{attr: map[string]map[string]string{}},
+	}
+	if err := p.graph(); err != nil {
+		return nil, fmt.Errorf("DOT: %v", err)
+	}
+	return p.g, nil
+}
+
+// graph parses:  [strict] (graph | digraph) [ID] '{' stmt_list '}'
+func (p *dotParser) graph() error {
+	t, _, err := p.token()
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(t, "strict") {
+		if t, _, err = p.token(); err != nil {
+			return err
+		}
+	}
+	switch strings.ToLower(t) {
+	case "digraph":
+		p.g.directed = true
+	case "graph":
+	default:
+		return fmt.Errorf("expected graph or digraph, found %q", t)
+	}
+	if t, _, err = p.token(); err != nil {
+		return err
+	}
+	if t != "{" {
+		if t, _, err = p.token(); err != nil { // skip graph ID
+			return err
+		}
+	}
+	if t != "{" {
+		return fmt.Errorf("expected {, found %q", t)
+	}
+	for {
+		t, quoted, err := p.token()
+		if err != nil {
+			return err
+		}
+		switch {
+		case t == "}" && !quoted:
+			return nil
+		case t == ";" && !quoted:
+			continue
+		case !quoted && (t == "{" || strings.EqualFold(t, "subgraph")):
+			return errors.New("subgraphs not supported")
+		}
+		if err := p.stmt(t, quoted); err != nil {
+			return err
+		}
+	}
+}
+
+// stmt parses a statement starting with token t.
+func (p *dotParser) stmt(t string, quoted bool) error {
+	if !isID(t, quoted) {
+		return fmt.Errorf("unexpected %q", t)
+	}
+	if !quoted {
+		switch strings.ToLower(t) {
+		case "graph", "node", "edge":
+			if _, err := p.attrList(); err != nil { // attribute statement, ignored
+				return err
+			}
+			return p.stmtEnd()
+		}
+	}
+	if p.peek() == '=' { // graph attribute assignment, ignored
+		p.pos++
+		if _, err := p.id(); err != nil {
+			return err
+		}
+		return p.stmtEnd()
+	}
+	ids := []string{t}
+	for {
+		op := p.edgeOp()
+		if op == "" {
+			break
+		}
+		if (op == "->") != p.g.directed {
+			return fmt.Errorf("edge operator %s in wrong graph type", op)
+		}
+		id, err := p.id()
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	attr, err := p.attrList()
+	if err != nil {
+		return err
+	}
+	if err := p.stmtEnd(); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		p.node(id)
+	}
+	if len(ids) == 1 {
+		for k, v := range attr {
+			p.g.attr[t][k] = v
+		}
+		return nil
+	}
+	for i := 1; i < len(ids); i++ {
+		p.g.edges = append(p.g.edges, dotEdge{ids[i-1], ids[i], attr})
+	}
+	return nil
+}
+
+// stmtEnd returns an error unless the next token can follow a statement.
+// It does not consume the token.
+func (p *dotParser) stmtEnd() error {
+	switch c := p.peek(); {
+	case c == 0, c == ';', c == '}', c == '"', isIDRune(c):
+		return nil
+	case c == ':':
+		return errors.New("ports not supported")
+	default:
+		return fmt.Errorf("unexpected %q", c)
+	}
+}
+
+// id returns the next token, which must be an ID.
+func (p *dotParser) id() (string, error) {
+	t, quoted, err := p.token()
+	if err != nil {
+		return "", err
+	}
+	if !isID(t, quoted) {
+		return "", fmt.Errorf("expected ID, found %q", t)
+	}
+	return t, nil
+}
+
+// isID returns true if t, as returned by token, is an ID rather than
+// punctuation.
+func isID(t string, quoted bool) bool {
+	return quoted || t != "" && isIDRune([]rune(t)[0])
+}
+
+// isIDRune returns true if c can be part of an unquoted ID.
+func isIDRune(c rune) bool {
+	return c == '_' || c == '.' || c == '-' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// node records node ID id, if new.
+func (p *dotParser) node(id string) {
+	if _, ok := p.g.attr[id]; !ok {
+		p.g.attr[id] = map[string]string{}
+		p.g.nodes = append(p.g.nodes, id)
+	}
+}
+
+// edgeOp consumes and returns an edge operator if one is next.
+func (p *dotParser) edgeOp() string {
+	p.space()
+	if p.pos+1 < len(p.s) && p.s[p.pos] == '-' {
+		if c := p.s[p.pos+1]; c == '>' || c == '-' {
+			p.pos += 2
+			return string(p.s[p.pos-2 : p.pos])
+		}
+	}
+	return ""
+}
+
+// attrList parses zero or more attribute lists:  '[' a_list ']'
+func (p *dotParser) attrList() (map[string]string, error) {
+	attr := map[string]string{}
+	for p.peek() == '[' {
+		p.pos++
+		for {
+			k, quoted, err := p.token()
+			if err != nil {
+				return nil, err
+			}
+			if k == "]" && !quoted {
+				break
+			}
+			if (k == "," || k == ";") && !quoted {
+				continue
+			}
+			if !isID(k, quoted) {
+				return nil, fmt.Errorf("unexpected %q in attribute list", k)
+			}
+			if p.peek() != '=' {
+				return nil, fmt.Errorf("expected = after attribute %q", k)
+			}
+			p.pos++
+			v, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			attr[k] = v
+		}
+	}
+	return attr, nil
+}
+
+// peek skips space and comments and returns the next rune, or 0 at end.
+func (p *dotParser) peek() rune {
+	p.space()
+	if p.pos == len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+// space skips white space and comments.
+func (p *dotParser) space() {
+	for p.pos < len(p.s) {
+		switch c := p.s[p.pos]; {
+		case unicode.IsSpace(c):
+			p.pos++
+		case c == '#' || c == '/' && p.pos+1 < len(p.s) && p.s[p.pos+1] == '/':
+			for p.pos < len(p.s) && p.s[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '/' && p.pos+1 < len(p.s) && p.s[p.pos+1] == '*':
+			p.pos += 2
+			for p.pos < len(p.s) && !(p.s[p.pos] == '*' &&
+				p.pos+1 < len(p.s) && p.s[p.pos+1] == '/') {
+				p.pos++
+			}
+			p.pos += 2
+			if p.pos > len(p.s) {
+				p.pos = len(p.s)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// token returns the next token:  an ID, a quoted string, or a single
+// punctuation character.  Quoted is true for quoted strings.
+func (p *dotParser) token() (t string, quoted bool, err error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return "", false, io.ErrUnexpectedEOF
+	case c == '"':
+		var b strings.Builder
+		for p.pos++; p.pos < len(p.s); p.pos++ {
+			c := p.s[p.pos]
+			switch {
+			case c == '"':
+				p.pos++
+				return b.String(), true, nil
+			case c == '\\' && p.pos+1 < len(p.s):
+				p.pos++
+				switch c = p.s[p.pos]; c {
+				case '"', '\\':
+					b.WriteRune(c)
+				case 'n':
+					b.WriteRune('\n')
+				case '\n': // line continuation
+				default:
+					b.WriteRune('\\')
+					b.WriteRune(c)
+				}
+			default:
+				b.WriteRune(c)
+			}
+		}
+		return "", false, errors.New("unterminated string")
+	case isIDRune(c):
+		start := p.pos
+		for p.pos < len(p.s) {
+			c := p.s[p.pos]
+			if c == '-' && p.pos+1 < len(p.s) && (p.s[p.pos+1] == '>' || p.s[p.pos+1] == '-') {
+				break // edge operator
+			}
+			if !isIDRune(c) {
+				break
+			}
+			p.pos++
+		}
+		return string(p.s[start:p.pos]), false, nil
+	}
+	p.pos++
+	return string(c), false, nil
+}
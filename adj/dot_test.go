@@ -0,0 +1,201 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph2/adj"
+	"github.com/soniakeys/graph2/search"
+)
+
+func ExampleDigraph_WriteDOT() {
+	g := adj.Digraph{}
+	g.Link("a", "b", adj.Weighted(7))
+	g.Link("a", "c", adj.Weighted(9))
+	g.Link("b", "c", adj.Weighted(1))
+	g.Link("c", "a", nil)
+	path, _ := search.DijkstraShortestPath(g["a"], g["c"])
+	g.WriteDOT(os.Stdout, &adj.DOTConfig{Name: "g", Path: path})
+	// Output:
+	// digraph "g" {
+	// 	n0 [label="a" color=red];
+	// 	n1 [label="b" color=red];
+	// 	n2 [label="c" color=red];
+	// 	n0 -> n1 [label="7" color=red penwidth=2];
+	// 	n0 -> n2 [label="9"];
+	// 	n1 -> n2 [label="1" color=red penwidth=2];
+	// 	n2 -> n0;
+	// }
+}
+
+func ExampleGraph_WriteDOT() {
+	g := adj.NewGraph()
+	g.Link(1, 2, "x")
+	g.Link(3, 1, "y")
+	g.WriteDOT(os.Stdout, nil)
+	// Output:
+	// graph {
+	// 	n0 [label="1"];
+	// 	n1 [label="2"];
+	// 	n2 [label="3"];
+	// 	n0 -- n1 [label="x"];
+	// 	n2 -- n0 [label="y"];
+	// }
+}
+
+func ExampleReadDOTDigraph() {
+	g, err := adj.ReadDOTDigraph(strings.NewReader(`
+		// a small network
+		digraph net {
+			rankdir=LR
+			node [shape=box]
+			a -> b -> c [weight=2]
+			a -> c [label="slow"]
+			"d e"; /* isolated */
+		}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var s []string
+	for k, nd := range g {
+		s = append(s, fmt.Sprintf("%q %v", k, nd.Nbs))
+	}
+	sort.Strings(s)
+	for _, s := range s {
+		fmt.Println(s)
+	}
+	// Output:
+	// "a" [{2 b} {slow c}]
+	// "b" [{2 c}]
+	// "c" []
+	// "d e" []
+}
+
+func TestDOTRoundTrip(t *testing.T) {
+	g := adj.Digraph{}
+	g.Link(`q"uote`, "b", adj.Weighted(1.5))
+	g.Link("b", "c", "lbl")
+	g.Link("c", `q"uote`, nil)
+	g.Link("c", "c", adj.Weighted(2))
+	var b bytes.Buffer
+	if err := g.WriteDOT(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	g2, err := adj.ReadDOTDigraph(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g2) != len(g) {
+		t.Fatal(len(g2), "nodes, want", len(g))
+	}
+	// keys of g2 are DOT IDs, Data of g2 the keys of g
+	byData := map[interface{}]*adj.Node{}
+	for _, nd := range g2 {
+		byData[nd.Data] = nd
+	}
+	for k, nd := range g {
+		nd2 := byData[k]
+		if nd2 == nil {
+			t.Fatal("missing node", k)
+		}
+		if fmt.Sprint(nd.Nbs) != fmt.Sprint(nd2.Nbs) {
+			t.Fatal(k, nd.Nbs, nd2.Nbs)
+		}
+	}
+	u := adj.NewGraph()
+	u.Link("a", "b", adj.Weighted(3))
+	u.Link("b", "c", nil)
+	b.Reset()
+	u.WriteDOT(&b, nil)
+	u2, err := adj.ReadDOTGraph(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(u2.Nodes) != 3 || len(u2.Edges) != 2 || !u2.Connected("n0", "n2") ||
+		u2.Nodes["n0"].Data != "a" || u2.Nodes["n2"].Data != "c" {
+		t.Fatal("undirected round trip", u2.Nodes, u2.Edges)
+	}
+}
+
+func TestWriteDOTRepeatable(t *testing.T) {
+	// nodes with equal labels are written in the same order every time
+	g := adj.Digraph{}
+	for i := 0; i < 10; i++ {
+		g.Link(i, strconv.Itoa(i), adj.Weighted(i))
+	}
+	var b0 bytes.Buffer
+	g.WriteDOT(&b0, nil)
+	for i := 0; i < 20; i++ {
+		var b bytes.Buffer
+		g.WriteDOT(&b, nil)
+		if b.String() != b0.String() {
+			t.Fatal("output differs:\n", b0.String(), "\n", b.String())
+		}
+	}
+}
+
+func TestReadDOTSameLabel(t *testing.T) {
+	// nodes with equal labels, or equal String values, remain distinct
+	g := adj.Digraph{}
+	g.Link(1, "1", nil)
+	g.Link("1", 2, nil)
+	var b bytes.Buffer
+	g.WriteDOT(&b, nil)
+	g2, err := adj.ReadDOTDigraph(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g2) != 3 || g2.NumEdges() != 2 {
+		t.Fatal(b.String(), g2)
+	}
+	g2, err = adj.ReadDOTDigraph(strings.NewReader(
+		`digraph { a [label=x]; b [label=x]; c; a -> b -> c }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g2) != 3 || g2["a"].Data != "x" || g2["b"].Data != "x" ||
+		g2["c"].Data != "c" || len(g2["a"].Nbs) != 1 || g2["a"].Nbs[0].To != g2["b"] {
+		t.Fatal(g2)
+	}
+}
+
+func TestReadDOTErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"tree {}",
+		"digraph { a -- b }",
+		"digraph { a -> }",
+		"digraph { subgraph s { a } }",
+		`digraph { "a }`,
+		"digraph { a [label] }",
+		"digraph { a -> b",
+		"digraph { a:p1 -> b:p2 }",       // ports
+		"digraph { a -> b:p2 }",          // port
+		"digraph { a -> ; }",             // missing node
+		"digraph { -> b }",               // missing node
+		"digraph { a -> b [w=1] ) }",     // stray punctuation
+		"digraph { a [=1] }",             // missing attribute name
+		"digraph { a [label=,] }",        // missing attribute value
+		"digraph { rankdir = ; }",        // missing value
+		"digraph { node [shape=box] : }", // stray punctuation
+	} {
+		if _, err := adj.ReadDOTDigraph(strings.NewReader(s)); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+	if _, err := adj.ReadDOTGraph(strings.NewReader("digraph {}")); err == nil {
+		t.Error("ReadDOTGraph read a digraph")
+	}
+	if _, err := adj.ReadDOTDigraph(strings.NewReader("graph {}")); err == nil {
+		t.Error("ReadDOTDigraph read a graph")
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/soniakeys/graph2"
+)
+
+// ReadEdgeList reads a directed graph from a plain edge list such as those
+// of the Stanford Large Network Dataset Collection (SNAP).
+//
+// Each line holds a from-node and a to-node, as integers separated by
+// white space, optionally followed by a numeric weight.  Lines that are
+// blank or start with # or % are comments.  Input is read as a stream.
+//
+// Nodes are keyed by their integer IDs.  Keys and Data are ints.  Arcs are
+// adj.Weighted, with weight 1 where no weight is given.
+func ReadEdgeList(r io.Reader) (Digraph, error) {
+	g := Digraph{}
+	node := func(id int) *Node {
+		nd, ok := g[id]
+		if !ok {
+			nd = &Node{Data: id}
+			g[id] = nd
+		}
+		return nd
+	}
+	err := scanLines(r, func(f [][]byte) error {
+		if c := f[0][0]; c == '#' || c == '%' {
+			return nil
+		}
+		if len(f) < 2 || len(f) > 3 {
+			return fmt.Errorf("expected 2 or 3 fields, found %d", len(f))
+		}
+		from, err := atoi(f[0])
+		if err != nil {
+			return err
+		}
+		to, err := atoi(f[1])
+		if err != nil {
+			return err
+		}
+		w := 1.
+		if len(f) == 3 {
+			if w, err = strconv.ParseFloat(string(f[2]), 64); err != nil {
+				return err
+			}
+		}
+		link(node(from), node(to), Weighted(w))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("edge list %v", err)
+	}
+	return g, nil
+}
+
+// link adds an arc from nd1 to nd2.
+func link(nd1, nd2 *Node, arc graph2.Arc) {
+	nd1.Nbs = append(nd1.Nbs, graph2.Half{arc, nd2})
+	nd2.In = append(nd2.In, graph2.FromHalf{nd1, arc})
+}
+
+// scanLines calls f with the white space separated fields of each non-blank
+// line of r.  Errors are annotated with the line number.
+func scanLines(r io.Reader, f func([][]byte) error) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	var fields [][]byte
+	for n := 1; s.Scan(); n++ {
+		fields = fields[:0]
+		for b := s.Bytes(); ; {
+			b = bytes.TrimLeft(b, " \t\r")
+			if len(b) == 0 {
+				break
+			}
+			i := bytes.IndexAny(b, " \t\r")
+			if i < 0 {
+				i = len(b)
+			}
+			fields = append(fields, b[:i])
+			b = b[i:]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		if err := f(fields); err != nil {
+			return fmt.Errorf("line %d: %v", n, err)
+		}
+	}
+	return s.Err()
+}
+
+// atoi parses a decimal integer without converting b to a string.
+func atoi(b []byte) (int, error) {
+	neg := false
+	s := b
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if len(s) == 0 || len(s) > 18 {
+		return strconv.Atoi(string(b)) // for the error, or a large value
+	}
+	n := 0
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return strconv.Atoi(string(b))
+		}
+		n = n*10 + int(c-'0')
+	}
+	if neg {
+		n = -n
+	}
+	return n, nil
+}
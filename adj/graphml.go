@@ -0,0 +1,396 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/soniakeys/graph2"
+)
+
+// GraphMLNode is the Data of a node read from GraphML.
+type GraphMLNode struct {
+	ID   string            // GraphML node id, also the node key
+	Attr map[string]string // data values by attribute name
+}
+
+// String returns the node ID.
+func (n *GraphMLNode) String() string { return n.ID }
+
+// GraphMLEdge is the value of an arc or edge read from GraphML that has
+// data other than a weight.
+type GraphMLEdge struct {
+	ID   string            // GraphML edge id, possibly empty
+	Attr map[string]string // data values by attribute name
+}
+
+// Weight returns the value of the "weight" attribute, or 1 if e has no
+// weight attribute that parses as a number.
+//
+// GraphMLEdge implements graph2.Weighted so that graphs read from GraphML
+// can be searched directly.  The attribute is parsed on each call.
+func (e *GraphMLEdge) Weight() float64 {
+	if f, err := strconv.ParseFloat(e.Attr["weight"], 64); err == nil {
+		return f
+	}
+	return 1
+}
+
+// String returns the edge ID.
+func (e *GraphMLEdge) String() string { return e.ID }
+
+// GraphML documents, as encoded and decoded by package encoding/xml.
+type gmlDoc struct {
+	XMLName xml.Name   `xml:"graphml"`
+	Xmlns   string     `xml:"xmlns,attr,omitempty"`
+	Keys    []gmlKey   `xml:"key"`
+	Graphs  []gmlGraph `xml:"graph"`
+}
+
+type gmlKey struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr,omitempty"`
+	Name    string  `xml:"attr.name,attr,omitempty"`
+	Type    string  `xml:"attr.type,attr,omitempty"`
+	Default *string `xml:"default"`
+}
+
+type gmlGraph struct {
+	ID          string    `xml:"id,attr,omitempty"`
+	EdgeDefault string    `xml:"edgedefault,attr"`
+	Nodes       []gmlNode `xml:"node"`
+	Edges       []gmlEdge `xml:"edge"`
+}
+
+type gmlNode struct {
+	ID   string    `xml:"id,attr"`
+	Data []gmlData `xml:"data"`
+}
+
+type gmlEdge struct {
+	ID       string    `xml:"id,attr,omitempty"`
+	Source   string    `xml:"source,attr"`
+	Target   string    `xml:"target,attr"`
+	Directed string    `xml:"directed,attr,omitempty"`
+	Data     []gmlData `xml:"data"`
+}
+
+type gmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+const graphMLNS = "http://graphml.graphdrawing.org/xmlns"
+
+// WriteGraphML writes g as a GraphML document.
+//
+// Nodes with Data of type *GraphMLNode are written with the node ID and
+// attributes of the Data.  Other nodes are given IDs n0, n1, ... in order
+// of Node.String, then of node key, and a "label" attribute of Node.String.  Arcs of type
+// *GraphMLEdge are written with their ID and attributes, other arcs
+// implementing graph2.Weighted with a "weight" attribute, and other non-nil
+// arcs with a "label" attribute of a string representation of the arc
+// value.  Output is repeatable.
+//
+// WriteGraphML returns an error if node IDs are not unique.
+func (g Digraph) WriteGraphML(w io.Writer) error {
+	return writeGraphML(w, true, g, func(nd *Node, f func(to *Node, ed interface{})) {
+		for _, h := range nd.Nbs {
+			f(h.To.(*Node), h.Ed)
+		}
+	})
+}
+
+// WriteGraphML writes g as a GraphML document with edgedefault
+// "undirected".  Each edge is written once.  Nodes and edges are written
+// as documented for Digraph.WriteGraphML.
+func (g Graph) WriteGraphML(w io.Writer) error {
+	out := map[*Node][]graph2.Half{}
+	for k, ed := range g.Edges {
+		out[k.n1] = append(out[k.n1], graph2.Half{ed, k.n2})
+	}
+	return writeGraphML(w, false, g.Nodes, func(nd *Node, f func(to *Node, ed interface{})) {
+		for _, h := range out[nd] {
+			f(h.To.(*Node), h.Ed)
+		}
+	})
+}
+
+// writeGraphML writes nodes m, with arcs or edges from each node as visited
+// by function arcs.
+func writeGraphML(w io.Writer, directed bool, m map[interface{}]*Node, arcs func(*Node, func(*Node, interface{}))) error {
+	nodes := sortedNodes(m)
+	// node IDs and attributes
+	id := make(map[*Node]string, len(nodes))
+	x := make(map[*Node]int, len(nodes))
+	seen := make(map[string]bool, len(nodes))
+	attr := make([]map[string]string, len(nodes))
+	nodeKeys := map[string]string{} // attribute name -> attr.type
+	for i, nd := range nodes {
+		x[nd] = i
+		if gn, ok := nd.Data.(*GraphMLNode); ok {
+			id[nd] = gn.ID
+			attr[i] = gn.Attr
+			for n := range gn.Attr {
+				nodeKeys[n] = "string"
+			}
+		} else {
+			id[nd] = "n" + strconv.Itoa(i)
+			attr[i] = map[string]string{"label": nd.String()}
+			nodeKeys["label"] = "string"
+		}
+		if seen[id[nd]] {
+			return fmt.Errorf("GraphML: duplicate node id %q", id[nd])
+		}
+		seen[id[nd]] = true
+	}
+	// edges
+	type arc struct {
+		to *Node
+		ed interface{}
+	}
+	var edges []gmlEdge
+	var edgeAttr []map[string]string
+	edgeKeys := map[string]string{}
+	var out []arc
+	for _, nd := range nodes {
+		out = out[:0]
+		arcs(nd, func(to *Node, ed interface{}) { out = append(out, arc{to, ed}) })
+		if !directed {
+			// edges of a Graph come from a map.  sort for repeatability.
+			sort.Slice(out, func(i, j int) bool { return x[out[i].to] < x[out[j].to] })
+		}
+		for _, a := range out {
+			e := gmlEdge{Source: id[nd], Target: id[a.to]}
+			var ea map[string]string
+			switch ed := a.ed.(type) {
+			case nil:
+			case *GraphMLEdge:
+				e.ID = ed.ID
+				ea = ed.Attr
+			case graph2.Weighted:
+				ea = map[string]string{
+					"weight": strconv.FormatFloat(ed.Weight(), 'g', -1, 64)}
+			default:
+				ea = map[string]string{"label": fmt.Sprint(ed)}
+			}
+			for n := range ea {
+				edgeKeys[n] = "string"
+			}
+			edges = append(edges, e)
+			edgeAttr = append(edgeAttr, ea)
+		}
+	}
+	if _, ok := edgeKeys["weight"]; ok {
+		edgeKeys["weight"] = "double"
+	}
+	doc := gmlDoc{Xmlns: graphMLNS, Graphs: []gmlGraph{{EdgeDefault: "undirected"}}}
+	g := &doc.Graphs[0]
+	if directed {
+		g.EdgeDefault = "directed"
+	}
+	nk := gmlKeys(&doc, "node", nodeKeys)
+	ek := gmlKeys(&doc, "edge", edgeKeys)
+	for i, nd := range nodes {
+		g.Nodes = append(g.Nodes,
+			gmlNode{ID: id[nd], Data: gmlDataList(attr[i], nk)})
+	}
+	for i := range edges {
+		edges[i].Data = gmlDataList(edgeAttr[i], ek)
+	}
+	g.Edges = edges
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// gmlKeys adds key elements to doc for attribute names and types in attr,
+// and returns key IDs by attribute name.
+func gmlKeys(doc *gmlDoc, kind string, attr map[string]string) map[string]string {
+	names := make([]string, 0, len(attr))
+	for n := range attr {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	ids := make(map[string]string, len(names))
+	for _, n := range names {
+		k := gmlKey{
+			ID:   "d" + strconv.Itoa(len(doc.Keys)),
+			For:  kind,
+			Name: n,
+			Type: attr[n],
+		}
+		doc.Keys = append(doc.Keys, k)
+		ids[n] = k.ID
+	}
+	return ids
+}
+
+// gmlDataList returns data elements for attr, in order of key ID.
+func gmlDataList(attr map[string]string, ids map[string]string) []gmlData {
+	d := make([]gmlData, 0, len(attr))
+	for n, v := range attr {
+		d = append(d, gmlData{ids[n], v})
+	}
+	sort.Slice(d, func(i, j int) bool {
+		return len(d[i].Key) < len(d[j].Key) ||
+			len(d[i].Key) == len(d[j].Key) && d[i].Key < d[j].Key
+	})
+	return d
+}
+
+// ReadGraphMLDigraph reads a directed graph from a GraphML document.
+//
+// The first graph of the document is read.  Nested graphs, hyperedges,
+// and ports are ignored.  Edges declared undirected, either individually
+// or by the graph edgedefault, are read as a pair of arcs, one in each
+// direction.
+//
+// Nodes are keyed by their GraphML IDs.  Node Data is a *GraphMLNode
+// holding the ID and data values by the attr.name of their keys, or by key
+// ID for keys without an attr.name.  Key defaults are applied.  An
+// attribute named "weight", in any case, is stored as "weight".
+//
+// An edge with no data has a nil value.  An edge with only a weight that
+// parses as a number has an adj.Weighted value.  Other edges have
+// *GraphMLEdge values.  Thus graphs with weighted edges can be searched
+// with functions of package graph/search.
+func ReadGraphMLDigraph(r io.Reader) (Digraph, error) {
+	gg, keys, err := parseGraphML(r)
+	if err != nil {
+		return nil, err
+	}
+	g := Digraph{}
+	gg.build(keys, func(k string, gn *GraphMLNode) {
+		if _, ok := g[k]; !ok {
+			g[k] = &Node{Data: gn}
+		}
+	}, func(n1, n2 string, ed interface{}, directed bool) {
+		g.Link(n1, n2, ed)
+		if !directed && n1 != n2 {
+			g.Link(n2, n1, ed)
+		}
+	})
+	return g, nil
+}
+
+// ReadGraphMLGraph reads an undirected graph from a GraphML document.
+//
+// All edges are read as undirected, regardless of edgedefault or directed
+// attributes.  Parallel edges after the first are ignored as documented
+// for Graph.Link.  Otherwise the document is read as documented for
+// ReadGraphMLDigraph.
+func ReadGraphMLGraph(r io.Reader) (Graph, error) {
+	gg, keys, err := parseGraphML(r)
+	if err != nil {
+		return Graph{}, err
+	}
+	g := NewGraph()
+	gg.build(keys, func(k string, gn *GraphMLNode) {
+		if _, ok := g.Nodes[k]; !ok {
+			g.Nodes[k] = &Node{Data: gn}
+		}
+	}, func(n1, n2 string, ed interface{}, directed bool) { g.Link(n1, n2, ed) })
+	return g, nil
+}
+
+// gmlAttrKey is a parsed key element.
+type gmlAttrKey struct {
+	name, def string
+	hasDef    bool
+}
+
+// parseGraphML decodes r and returns the first graph and the keys by ID,
+// separately for nodes and edges.
+func parseGraphML(r io.Reader) (*gmlGraph, map[string]map[string]gmlAttrKey, error) {
+	var doc gmlDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("GraphML: %v", err)
+	}
+	if len(doc.Graphs) == 0 {
+		return nil, nil, errors.New("GraphML: no graph")
+	}
+	keys := map[string]map[string]gmlAttrKey{"node": {}, "edge": {}}
+	for _, k := range doc.Keys {
+		ak := gmlAttrKey{name: k.Name}
+		if ak.name == "" {
+			ak.name = k.ID
+		}
+		if strings.EqualFold(ak.name, "weight") {
+			ak.name = "weight"
+		}
+		if k.Default != nil {
+			ak.def, ak.hasDef = strings.TrimSpace(*k.Default), true
+		}
+		switch k.For {
+		case "node", "edge":
+			keys[k.For][k.ID] = ak
+		case "", "all":
+			keys["node"][k.ID] = ak
+			keys["edge"][k.ID] = ak
+		}
+	}
+	return &doc.Graphs[0], keys, nil
+}
+
+// gmlAttr returns attributes of data, with key defaults applied.
+func gmlAttr(data []gmlData, keys map[string]gmlAttrKey) map[string]string {
+	a := map[string]string{}
+	for _, k := range keys {
+		if k.hasDef {
+			a[k.name] = k.def
+		}
+	}
+	for _, d := range data {
+		k, ok := keys[d.Key]
+		if !ok {
+			k.name = d.Key
+		}
+		a[k.name] = strings.TrimSpace(d.Value)
+	}
+	return a
+}
+
+// build adds nodes and edges of gg to a graph with functions add and link.
+// Nodes referenced by edges but not declared are added with no attributes.
+func (gg *gmlGraph) build(keys map[string]map[string]gmlAttrKey, add func(string, *GraphMLNode), link func(n1, n2 string, ed interface{}, directed bool)) {
+	for _, n := range gg.Nodes {
+		add(n.ID, &GraphMLNode{n.ID, gmlAttr(n.Data, keys["node"])})
+	}
+	for _, e := range gg.Edges {
+		add(e.Source, &GraphMLNode{e.Source, map[string]string{}})
+		add(e.Target, &GraphMLNode{e.Target, map[string]string{}})
+		var ed interface{}
+		a := gmlAttr(e.Data, keys["edge"])
+		if len(a) > 0 {
+			ed = &GraphMLEdge{e.ID, a}
+		}
+		if w, ok := a["weight"]; ok && len(a) == 1 {
+			if f, err := strconv.ParseFloat(w, 64); err == nil {
+				ed = Weighted(f)
+			}
+		}
+		directed := gg.EdgeDefault != "undirected"
+		switch e.Directed {
+		case "true":
+			directed = true
+		case "false":
+			directed = false
+		}
+		link(e.Source, e.Target, ed, directed)
+	}
+}
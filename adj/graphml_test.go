@@ -0,0 +1,210 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph2/adj"
+	"github.com/soniakeys/graph2/search"
+)
+
+const gmlRoads = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="k0" for="node" attr.name="city" attr.type="string"/>
+  <key id="k1" for="edge" attr.name="Weight" attr.type="double">
+    <default>10</default>
+  </key>
+  <key id="k2" for="edge" attr.name="road" attr.type="string"/>
+  <graph id="G" edgedefault="directed">
+    <node id="a"><data key="k0">Arles</data></node>
+    <node id="b"><data key="k0">Beziers</data></node>
+    <node id="c"/>
+    <edge source="a" target="b"><data key="k1">3</data></edge>
+    <edge source="b" target="c"/>
+    <edge id="e2" source="a" target="c">
+      <data key="k1">20</data><data key="k2">N113</data>
+    </edge>
+    <edge source="c" target="d" directed="false"><data key="k1">1</data></edge>
+  </graph>
+</graphml>`
+
+func ExampleReadGraphMLDigraph() {
+	g, err := adj.ReadGraphMLDigraph(strings.NewReader(gmlRoads))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(g["a"].Data.(*adj.GraphMLNode).Attr["city"])
+	path, dist := search.DijkstraShortestPath(g["a"], g["d"])
+	fmt.Println(path, dist)
+	// Output:
+	// Arles
+	// [{<nil> a} {3 b} {10 c} {1 d}] 14
+}
+
+func ExampleGraph_WriteGraphML() {
+	g := adj.NewGraph()
+	g.Link("a", "b", adj.Weighted(2))
+	g.Link("b", "c", "x")
+	g.WriteGraphML(os.Stdout)
+	// Output:
+	// <?xml version="1.0" encoding="UTF-8"?>
+	// <graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	// 	<key id="d0" for="node" attr.name="label" attr.type="string"></key>
+	// 	<key id="d1" for="edge" attr.name="label" attr.type="string"></key>
+	// 	<key id="d2" for="edge" attr.name="weight" attr.type="double"></key>
+	// 	<graph edgedefault="undirected">
+	// 		<node id="n0">
+	// 			<data key="d0">a</data>
+	// 		</node>
+	// 		<node id="n1">
+	// 			<data key="d0">b</data>
+	// 		</node>
+	// 		<node id="n2">
+	// 			<data key="d0">c</data>
+	// 		</node>
+	// 		<edge source="n0" target="n1">
+	// 			<data key="d2">2</data>
+	// 		</edge>
+	// 		<edge source="n1" target="n2">
+	// 			<data key="d1">x</data>
+	// 		</edge>
+	// 	</graph>
+	// </graphml>
+}
+
+func TestReadGraphMLDigraph(t *testing.T) {
+	g, err := adj.ReadGraphMLDigraph(strings.NewReader(gmlRoads))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReadDigraph(t, g)
+	if len(g) != 4 || g.NumEdges() != 5 {
+		t.Fatalf("got %d nodes, %d arcs", len(g), g.NumEdges())
+	}
+	if d := g["d"].Data.(*adj.GraphMLNode); d.ID != "d" || len(d.Attr) != 0 {
+		t.Fatalf("undeclared node d: %#v", d)
+	}
+	want := &adj.GraphMLEdge{"e2", map[string]string{"road": "N113", "weight": "20"}}
+	for _, h := range g["a"].Nbs {
+		if h.To == g["c"] {
+			if !reflect.DeepEqual(h.Ed, want) {
+				t.Fatalf("a->c = %#v", h.Ed)
+			}
+			if w := h.Ed.(*adj.GraphMLEdge).Weight(); w != 20 {
+				t.Fatalf("a->c weight %g", w)
+			}
+		}
+	}
+	if len(g["d"].Nbs) != 1 || g["d"].Nbs[0].Ed != adj.Weighted(1) {
+		t.Fatalf("undirected edge c-d: %v", g["d"].Nbs)
+	}
+	if _, err := adj.ReadGraphMLDigraph(strings.NewReader("<graphml/>")); err == nil {
+		t.Fatal("no error for document with no graph")
+	}
+	if _, err := adj.ReadGraphMLDigraph(strings.NewReader("<graphml><graph>")); err == nil {
+		t.Fatal("no error for bad XML")
+	}
+}
+
+func TestWriteGraphMLRepeatable(t *testing.T) {
+	// nodes with equal labels are written in the same order every time
+	g := adj.Digraph{}
+	for i := 0; i < 10; i++ {
+		g.Link(i, strconv.Itoa(i), adj.Weighted(i))
+	}
+	var b0 bytes.Buffer
+	g.WriteGraphML(&b0)
+	for i := 0; i < 20; i++ {
+		var b bytes.Buffer
+		g.WriteGraphML(&b)
+		if b.String() != b0.String() {
+			t.Fatal("output differs:\n", b0.String(), "\n", b.String())
+		}
+	}
+}
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	g, err := adj.ReadGraphMLDigraph(strings.NewReader(gmlRoads))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b1, b2 bytes.Buffer
+	if err := g.WriteGraphML(&b1); err != nil {
+		t.Fatal(err)
+	}
+	g2, err := adj.ReadGraphMLDigraph(bytes.NewReader(b1.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReadDigraph(t, g2)
+	if want, got := arcList(g), arcList(g2); !reflect.DeepEqual(want, got) {
+		t.Fatalf("round trip:\nwant %q\n got %q", want, got)
+	}
+	if err := g2.WriteGraphML(&b2); err != nil {
+		t.Fatal(err)
+	}
+	if b1.String() != b2.String() {
+		t.Fatalf("re-encoding differs:\n%s\n%s", &b1, &b2)
+	}
+
+	u, err := adj.ReadGraphMLGraph(strings.NewReader(gmlRoads))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReadGraph(t, u)
+	if len(u.Nodes) != 4 || u.NumEdges() != 4 {
+		t.Fatalf("got %d nodes, %d edges", len(u.Nodes), u.NumEdges())
+	}
+	b1.Reset()
+	if err := u.WriteGraphML(&b1); err != nil {
+		t.Fatal(err)
+	}
+	u2, err := adj.ReadGraphMLGraph(&b1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReadGraph(t, u2)
+	for k, nd := range u.Nodes {
+		nd2 := u2.Nodes[k]
+		if nd2 == nil || !reflect.DeepEqual(nd.Data, nd2.Data) ||
+			len(nd.Nbs) != len(nd2.Nbs) {
+			t.Fatalf("node %v: %v, %v", k, nd, nd2)
+		}
+	}
+
+	// duplicate IDs
+	d := adj.Digraph{}
+	d.Link(&adj.GraphMLNode{ID: "n1"}, "x", nil)
+	if err := d.WriteGraphML(&b1); err == nil {
+		t.Fatal("no error for duplicate node IDs")
+	}
+}
+
+func TestReadGraphMLTwoGraphs(t *testing.T) {
+	// only the first graph is read
+	g, err := adj.ReadGraphMLDigraph(strings.NewReader(`<graphml>
+  <graph edgedefault="directed">
+    <node id="a"/><node id="b"/>
+    <edge source="a" target="b"/>
+  </graph>
+  <graph edgedefault="directed">
+    <node id="c"/><node id="d"/>
+    <edge source="c" target="d"/>
+  </graph>
+</graphml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g) != 2 || g["a"] == nil || g["b"] == nil || g.NumEdges() != 1 {
+		t.Fatal(g)
+	}
+}
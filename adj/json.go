@@ -0,0 +1,345 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/internal"
+)
+
+// JSON encoding of adj graphs.
+//
+// A graph is encoded as an object with a node list and an edge list.
+// Edges reference nodes by index in the node list.  Node keys, node Data
+// that differs from the key, and arc or edge values are encoded as typed
+// values, objects holding a type tag and the JSON encoding of the value.
+// Types must be registered with RegisterJSON.  Types string, int, float64
+// and bool are registered by default.  Edge values of type Weighted are
+// encoded simply as a weight.  Nil values are omitted.
+//
+// For example, the digraph g with g.Link("a", "b", Weighted(3)) encodes
+// as
+//
+//	{"directed":true,
+//	 "nodes":[{"key":{"type":"string","value":"a"}},
+//	          {"key":{"type":"string","value":"b"}}],
+//	 "edges":[{"from":0,"to":1,"weight":3}]}
+
+type jsonGraph struct {
+	Directed bool       `json:"directed"`
+	Nodes    []jsonNode `json:"nodes"`
+	Edges    []jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	Key  *jsonValue `json:"key"`
+	Data *jsonValue `json:"data,omitempty"`
+}
+
+type jsonEdge struct {
+	From   int        `json:"from"`
+	To     int        `json:"to"`
+	Weight *float64   `json:"weight,omitempty"`
+	Value  *jsonValue `json:"value,omitempty"`
+}
+
+// jsonValue is a typed value.
+type jsonValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+var jsonTypes = struct {
+	sync.RWMutex
+	byTag  map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byTag:  map[string]reflect.Type{},
+	byType: map[reflect.Type]string{},
+}
+
+func init() {
+	RegisterJSON("string", "")
+	RegisterJSON("int", 0)
+	RegisterJSON("float64", 0.)
+	RegisterJSON("bool", false)
+}
+
+// RegisterJSON registers the concrete type of sample under tag for JSON
+// encoding of node keys, node Data, and arc or edge values.
+//
+// Values of a registered type are encoded with encoding/json and decoded
+// into a new value of the type.  The type must round trip through
+// encoding/json.  Like gob.RegisterName, RegisterJSON panics if tag or the
+// type is already registered differently.
+func RegisterJSON(tag string, sample interface{}) {
+	t := reflect.TypeOf(sample)
+	if t == nil {
+		panic("adj.RegisterJSON: nil sample")
+	}
+	jsonTypes.Lock()
+	defer jsonTypes.Unlock()
+	if t2, ok := jsonTypes.byTag[tag]; ok && t2 != t {
+		panic(fmt.Sprintf("adj.RegisterJSON: tag %q registered for %v", tag, t2))
+	}
+	if tag2, ok := jsonTypes.byType[t]; ok && tag2 != tag {
+		panic(fmt.Sprintf("adj.RegisterJSON: %v registered as %q", t, tag2))
+	}
+	jsonTypes.byTag[tag] = t
+	jsonTypes.byType[t] = tag
+}
+
+// encodeValue returns a typed value for v, or nil for a nil v.
+func encodeValue(v interface{}) (*jsonValue, error) {
+	if v == nil {
+		return nil, nil
+	}
+	t := reflect.TypeOf(v)
+	jsonTypes.RLock()
+	tag, ok := jsonTypes.byType[t]
+	jsonTypes.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("adj: type %v not registered for JSON", t)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonValue{tag, b}, nil
+}
+
+// decodeValue returns the value of a typed value, or nil for a nil tv.
+func decodeValue(tv *jsonValue) (interface{}, error) {
+	if tv == nil {
+		return nil, nil
+	}
+	jsonTypes.RLock()
+	t, ok := jsonTypes.byTag[tv.Type]
+	jsonTypes.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("adj: JSON type %q not registered", tv.Type)
+	}
+	p := reflect.New(t)
+	if err := json.Unmarshal(tv.Value, p.Interface()); err != nil {
+		return nil, err
+	}
+	return p.Elem().Interface(), nil
+}
+
+// encodeNodes returns the encoded nodes of m, sorted by encoded key for
+// repeatable output, and the index of each node in the list.
+func encodeNodes(m map[interface{}]*Node) ([]jsonNode, map[*Node]int, error) {
+	type keyed struct {
+		jn  jsonNode
+		nd  *Node
+		key []byte
+	}
+	ns := make([]keyed, 0, len(m))
+	for k, nd := range m {
+		kv, err := encodeValue(k)
+		if err != nil {
+			return nil, nil, err
+		}
+		if kv == nil {
+			return nil, nil, errors.New("adj: nil node key")
+		}
+		jn := jsonNode{Key: kv}
+		if !internal.Equal(k, nd.Data) {
+			if jn.Data, err = encodeValue(nd.Data); err != nil {
+				return nil, nil, err
+			}
+			if jn.Data == nil {
+				// nil Data differing from the key.  encode as JSON null.
+				jn.Data = &jsonValue{Value: json.RawMessage("null")}
+			}
+		}
+		key, _ := json.Marshal(kv)
+		ns = append(ns, keyed{jn, nd, key})
+	}
+	sort.Slice(ns, func(i, j int) bool { return bytes.Compare(ns[i].key, ns[j].key) < 0 })
+	nodes := make([]jsonNode, len(ns))
+	x := make(map[*Node]int, len(ns))
+	for i, n := range ns {
+		nodes[i] = n.jn
+		x[n.nd] = i
+	}
+	return nodes, x, nil
+}
+
+// encodeEdge encodes an arc or edge value.
+func encodeEdge(from, to int, ed interface{}) (jsonEdge, error) {
+	e := jsonEdge{From: from, To: to}
+	if w, ok := ed.(Weighted); ok {
+		f := float64(w)
+		e.Weight = &f
+		return e, nil
+	}
+	var err error
+	e.Value, err = encodeValue(ed)
+	return e, err
+}
+
+// decodeEdge decodes an arc or edge value.
+func (e jsonEdge) decode() (interface{}, error) {
+	if e.Weight != nil {
+		return Weighted(*e.Weight), nil
+	}
+	return decodeValue(e.Value)
+}
+
+// decodeNodes decodes nodes of jg into m, returning nodes by index.
+func decodeNodes(jg *jsonGraph, m map[interface{}]*Node) ([]*Node, error) {
+	nodes := make([]*Node, len(jg.Nodes))
+	for i, jn := range jg.Nodes {
+		k, err := decodeValue(jn.Key)
+		if err != nil {
+			return nil, err
+		}
+		if k == nil {
+			return nil, errors.New("adj: JSON node with no key")
+		}
+		// a key that does not equal itself cannot be used as a map key.
+		// it is not comparable, holds a value that is not comparable, or
+		// is a NaN.  using it would panic or lose the node.
+		if !internal.Equal(k, k) {
+			return nil, fmt.Errorf("adj: JSON node key %v of type %T not usable as map key", k, k)
+		}
+		if _, ok := m[k]; ok {
+			return nil, fmt.Errorf("adj: JSON duplicate node key %v", k)
+		}
+		nd := &Node{Data: k}
+		if jn.Data != nil {
+			if jn.Data.Type == "" {
+				nd.Data = nil
+			} else if nd.Data, err = decodeValue(jn.Data); err != nil {
+				return nil, err
+			}
+		}
+		m[k] = nd
+		nodes[i] = nd
+	}
+	for _, e := range jg.Edges {
+		if e.From < 0 || e.From >= len(nodes) || e.To < 0 || e.To >= len(nodes) {
+			return nil, fmt.Errorf("adj: JSON edge node index out of range")
+		}
+	}
+	return nodes, nil
+}
+
+// MarshalJSON implements json.Marshaler.  The encoding is described in the
+// package source.  Node keys, Data, and arc values must be of types
+// registered with RegisterJSON, or arcs of type Weighted, or nil.  Arc
+// order in Nbs of each node is preserved.
+func (g Digraph) MarshalJSON() ([]byte, error) {
+	nodes, x, err := encodeNodes(g)
+	if err != nil {
+		return nil, err
+	}
+	jg := jsonGraph{Directed: true, Nodes: nodes, Edges: []jsonEdge{}}
+	byIndex := make([]*Node, len(nodes))
+	for nd, i := range x {
+		byIndex[i] = nd
+	}
+	for i, nd := range byIndex {
+		for _, h := range nd.Nbs {
+			e, err := encodeEdge(i, x[h.To.(*Node)], h.Ed)
+			if err != nil {
+				return nil, err
+			}
+			jg.Edges = append(jg.Edges, e)
+		}
+	}
+	return json.Marshal(jg)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing *g with a new
+// Digraph decoded from data.
+func (g *Digraph) UnmarshalJSON(data []byte) error {
+	var jg jsonGraph
+	if err := json.Unmarshal(data, &jg); err != nil {
+		return err
+	}
+	if !jg.Directed {
+		return errors.New("adj: JSON graph is not directed")
+	}
+	d := Digraph{}
+	nodes, err := decodeNodes(&jg, d)
+	if err != nil {
+		return err
+	}
+	for _, e := range jg.Edges {
+		ed, err := e.decode()
+		if err != nil {
+			return err
+		}
+		nd1, nd2 := nodes[e.From], nodes[e.To]
+		nd1.Nbs = append(nd1.Nbs, graph2.Half{ed, nd2})
+		nd2.In = append(nd2.In, graph2.FromHalf{nd1, ed})
+	}
+	*g = d
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.  The encoding and requirements
+// are as documented for Digraph.MarshalJSON.
+func (g Graph) MarshalJSON() ([]byte, error) {
+	nodes, x, err := encodeNodes(g.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	jg := jsonGraph{Nodes: nodes, Edges: make([]jsonEdge, 0, len(g.Edges))}
+	for k, ed := range g.Edges {
+		e, err := encodeEdge(x[k.n1], x[k.n2], ed)
+		if err != nil {
+			return nil, err
+		}
+		jg.Edges = append(jg.Edges, e)
+	}
+	sort.Slice(jg.Edges, func(i, j int) bool {
+		ei, ej := jg.Edges[i], jg.Edges[j]
+		if ei.From != ej.From {
+			return ei.From < ej.From
+		}
+		return ei.To < ej.To
+	})
+	return json.Marshal(jg)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing *g with a new
+// Graph decoded from data.
+func (g *Graph) UnmarshalJSON(data []byte) error {
+	var jg jsonGraph
+	if err := json.Unmarshal(data, &jg); err != nil {
+		return err
+	}
+	if jg.Directed {
+		return errors.New("adj: JSON graph is directed")
+	}
+	u := NewGraph()
+	nodes, err := decodeNodes(&jg, u.Nodes)
+	if err != nil {
+		return err
+	}
+	key := make(map[*Node]interface{}, len(u.Nodes))
+	for k, nd := range u.Nodes {
+		key[nd] = k
+	}
+	for _, e := range jg.Edges {
+		ed, err := e.decode()
+		if err != nil {
+			return err
+		}
+		u.Link(key[nodes[e.From]], key[nodes[e.To]], ed)
+	}
+	*g = u
+	return nil
+}
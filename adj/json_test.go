@@ -0,0 +1,170 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph2/adj"
+)
+
+type city struct {
+	Name string
+	Pop  int
+}
+
+type road struct {
+	Route string
+	Km    float64
+}
+
+func (r road) Weight() float64 { return r.Km }
+
+// tags and tagged are registered types that cannot be node keys.  Tags is
+// not comparable.  Tagged is, but not when Tag holds a slice.
+type tags []string
+
+type tagged struct{ Tag interface{} }
+
+func init() {
+	adj.RegisterJSON("city", city{})
+	adj.RegisterJSON("road", road{})
+	adj.RegisterJSON("tags", tags{})
+	adj.RegisterJSON("tagged", tagged{})
+}
+
+func ExampleDigraph_MarshalJSON() {
+	g := adj.Digraph{}
+	g.Link("a", "b", adj.Weighted(3))
+	g.Link("b", "a", "back")
+	b, err := json.Marshal(g)
+	fmt.Println(string(b), err)
+	// Output:
+	// {"directed":true,"nodes":[{"key":{"type":"string","value":"a"}},{"key":{"type":"string","value":"b"}}],"edges":[{"from":0,"to":1,"weight":3},{"from":1,"to":0,"value":{"type":"string","value":"back"}}]} <nil>
+}
+
+// arcList lists arcs of g as strings, for comparison.
+func arcList(g adj.Digraph) []string {
+	var s []string
+	for k, nd := range g {
+		for _, h := range nd.Nbs {
+			s = append(s, fmt.Sprintf("%#v %#v %#v %#v",
+				k, nd.Data, h.To.(*adj.Node).Data, h.Ed))
+		}
+		if len(nd.Nbs) == 0 {
+			s = append(s, fmt.Sprintf("%#v %#v", k, nd.Data))
+		}
+	}
+	sort.Strings(s)
+	return s
+}
+
+func TestDigraphJSON(t *testing.T) {
+	g := adj.Digraph{}
+	g.Link(1, 2, adj.Weighted(1.5))
+	g.Link(1, 2, road{"A1", 12}) // parallel arc
+	g.Link(2, 2, nil)            // loop
+	g.Link(2, 3, true)
+	g["lone"] = &adj.Node{Data: "lone"}
+	g["nil data"] = &adj.Node{}
+	g[4] = &adj.Node{Data: city{"Oslo", 700000}}
+	b, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d adj.Digraph
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := arcList(g), arcList(d); !reflect.DeepEqual(want, got) {
+		t.Fatalf("round trip:\nwant %q\n got %q", want, got)
+	}
+	checkDigraph(t, d)
+	// encoding is repeatable
+	b2, _ := json.Marshal(d)
+	if string(b) != string(b2) {
+		t.Fatalf("re-encoding differs:\n%s\n%s", b, b2)
+	}
+}
+
+// edgeOf returns the edge between nodes with keys n1 and n2.
+func edgeOf(g adj.Graph, n1, n2 interface{}) (interface{}, bool) {
+	for _, h := range g.Nodes[n1].Nbs {
+		if h.To == g.Nodes[n2] {
+			return h.Ed, true
+		}
+	}
+	return nil, false
+}
+
+func TestGraphJSON(t *testing.T) {
+	g := adj.NewGraph()
+	g.Link("x", "y", adj.Weighted(2))
+	g.Link("y", "z", road{"E6", 40})
+	g.Link("z", "z", nil)
+	g.Nodes[city{"Bergen", 280000}] = &adj.Node{Data: city{"Bergen", 280000}}
+	b, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u adj.Graph
+	if err := json.Unmarshal(b, &u); err != nil {
+		t.Fatal(err)
+	}
+	checkGraph(t, u)
+	if len(u.Nodes) != len(g.Nodes) || len(u.Edges) != len(g.Edges) {
+		t.Fatalf("got %d nodes, %d edges", len(u.Nodes), len(u.Edges))
+	}
+	for _, e := range [][3]interface{}{
+		{"x", "y", adj.Weighted(2)},
+		{"y", "z", road{"E6", 40}},
+		{"z", "z", nil},
+	} {
+		ed, ok := edgeOf(u, e[0], e[1])
+		if !ok || ed != e[2] {
+			t.Fatalf("edge %v-%v = %v, %v", e[0], e[1], ed, ok)
+		}
+	}
+	b2, _ := json.Marshal(u)
+	if string(b) != string(b2) {
+		t.Fatalf("re-encoding differs:\n%s\n%s", b, b2)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	g := adj.Digraph{}
+	g.Link("a", "b", struct{}{})
+	if _, err := json.Marshal(g); err == nil {
+		t.Fatal("unregistered arc type encoded")
+	}
+	var d adj.Digraph
+	var u0 adj.Graph
+	for _, s := range []string{
+		`{"directed":false,"nodes":[],"edges":[]}`,
+		`{"directed":true,"nodes":[{"key":{"type":"nope","value":1}}]}`,
+		`{"directed":true,"nodes":[{"key":{"type":"int","value":1}}],"edges":[{"from":0,"to":1}]}`,
+		`{"directed":true,"nodes":[{"key":{"type":"int","value":1}},{"key":{"type":"int","value":1}}]}`,
+		`{"directed":true,"nodes":[{"key":{"type":"tags","value":["x"]}}]}`,
+		`{"directed":true,"nodes":[{"key":{"type":"tagged","value":{"Tag":["x"]}}}]}`,
+	} {
+		if err := json.Unmarshal([]byte(s), &d); err == nil {
+			t.Fatalf("no error decoding %s", s)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"directed":false,"nodes":[{"key":{"type":"tags","value":["x"]}}]}`), &u0); err == nil {
+		t.Fatal("no error decoding slice key as Graph")
+	}
+	// a registered type usable as a key when it holds a comparable value
+	if err := json.Unmarshal([]byte(`{"directed":true,"nodes":[{"key":{"type":"tagged","value":{"Tag":"x"}}}]}`), &d); err != nil {
+		t.Fatal(err)
+	}
+	var u adj.Graph
+	if err := json.Unmarshal([]byte(`{"directed":true}`), &u); err == nil {
+		t.Fatal("directed decoded as Graph")
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/soniakeys/graph2"
+)
+
+// Kruskal finds a minimum spanning forest of an undirected graph using
+// Kruskal's algorithm.
+//
+// Edges of g must implement graph2.Weighted.  Self loops are ignored.
+//
+// The forest is returned as a new Graph.  It has a node for each node of g,
+// with the same key and Data, and edges, with the same edge values, of a
+// minimum spanning tree of each connected component of g.  Also returned is
+// the total weight of the forest edges.
+func (g Graph) Kruskal() (forest Graph, weight float64) {
+	forest, key := g.emptyForest()
+	edges := g.weightedEdges()
+	sort.Slice(edges, func(i, j int) bool { return edges[i].w < edges[j].w })
+	u := &DisjointSet{}
+	for _, e := range edges {
+		if u.Union(e.n1, e.n2) {
+			forest.Link(key[e.n1], key[e.n2], e.ed)
+			weight += e.w
+		}
+	}
+	return
+}
+
+// Prim finds a minimum spanning forest of an undirected graph using
+// Prim's algorithm.
+//
+// Requirements and results are as documented for Kruskal.
+func (g Graph) Prim() (forest Graph, weight float64) {
+	forest, key := g.emptyForest()
+	// tx of a node is 0 if the node is not reached, the index in h.pool of
+	// its tentative data if it is reached, or -1 once it is in the tree.
+	tx := map[*Node]int{}
+	h := &primHeap{pool: make([]primPath, 1)} // zero element unused
+	for _, root := range g.Nodes {
+		if tx[root] != 0 {
+			continue
+		}
+		tx[root] = h.push(primPath{nd: root})
+		for len(h.heap) > 0 {
+			x := heap.Pop(h).(int)
+			p := h.pool[x]
+			h.free = append(h.free, x) // recycle pool element
+			tx[p.nd] = -1
+			if p.from != nil {
+				forest.Link(key[p.from], key[p.nd], p.ed)
+				weight += p.w
+			}
+			for _, nb := range p.nd.Nbs {
+				to := nb.To.(*Node)
+				t := tx[to]
+				if t < 0 {
+					continue // already in tree (or a self loop)
+				}
+				w := nb.Ed.(graph2.Weighted).Weight()
+				if t == 0 {
+					tx[to] = h.push(primPath{nd: to, from: p.nd, ed: nb.Ed, w: w})
+				} else if q := &h.pool[t]; w < q.w {
+					q.from = p.nd
+					q.ed = nb.Ed
+					q.w = w
+					heap.Fix(h, q.rx)
+				}
+			}
+		}
+	}
+	return
+}
+
+// Boruvka finds a minimum spanning forest of an undirected graph using
+// Borůvka's algorithm.
+//
+// Requirements and results are as documented for Kruskal.
+func (g Graph) Boruvka() (forest Graph, weight float64) {
+	forest, key := g.emptyForest()
+	edges := g.weightedEdges()
+	u := &DisjointSet{}
+	for {
+		// find cheapest edge leaving each component.  ties are broken
+		// by edge index so that equal weights cannot form a cycle.
+		cheapest := map[graph2.Node]int{}
+		for i, e := range edges {
+			c1, c2 := u.Find(e.n1), u.Find(e.n2)
+			if c1 == c2 {
+				continue
+			}
+			for _, c := range []graph2.Node{c1, c2} {
+				if j, ok := cheapest[c]; !ok || e.w < edges[j].w {
+					cheapest[c] = i
+				}
+			}
+		}
+		if len(cheapest) == 0 {
+			return
+		}
+		for _, i := range cheapest {
+			e := edges[i]
+			if u.Union(e.n1, e.n2) {
+				forest.Link(key[e.n1], key[e.n2], e.ed)
+				weight += e.w
+			}
+		}
+	}
+}
+
+// emptyForest returns a graph with the nodes of g but no edges, and a map
+// from nodes of g to their keys.
+func (g Graph) emptyForest() (Graph, map[*Node]interface{}) {
+	f := NewGraph()
+	key := make(map[*Node]interface{}, len(g.Nodes))
+	for k, nd := range g.Nodes {
+		f.Nodes[k] = &Node{Data: nd.Data}
+		key[nd] = k
+	}
+	return f, key
+}
+
+// wEdge is an edge with its weight.
+type wEdge struct {
+	n1, n2 *Node
+	ed     graph2.Edge
+	w      float64
+}
+
+// weightedEdges returns the edges of g, less self loops.
+func (g Graph) weightedEdges() []wEdge {
+	edges := make([]wEdge, 0, len(g.Edges))
+	for k, ed := range g.Edges {
+		if k.n1 != k.n2 {
+			edges = append(edges,
+				wEdge{k.n1, k.n2, ed, ed.(graph2.Weighted).Weight()})
+		}
+	}
+	return edges
+}
+
+// primPath holds data for a node reached but not yet in the tree.
+type primPath struct {
+	nd   *Node
+	from *Node       // tree node at other end of ed
+	ed   graph2.Edge // cheapest known edge connecting nd to the tree
+	w    float64     // weight of ed
+	rx   int         // heap.Fix index
+}
+
+// primHeap is an indexed heap like tentHeap of package search.  Path data
+// is kept in a pool and the heap holds pool indexes, so that the data of a
+// node can be found by index and reheaped when a cheaper edge is found.
+// Pool elements of nodes popped from the heap are reused.
+type primHeap struct {
+	pool []primPath
+	heap []int // values are indexes into pool
+	free []int // values are indexes into pool
+}
+
+// push adds p to the pool and the heap, returning its pool index.
+func (h *primHeap) push(p primPath) int {
+	var x int
+	if last := len(h.free) - 1; last >= 0 {
+		x = h.free[last]
+		h.free = h.free[:last]
+		h.pool[x] = p
+	} else {
+		x = len(h.pool)
+		h.pool = append(h.pool, p)
+	}
+	heap.Push(h, x)
+	return x
+}
+
+// implement container/heap
+func (h primHeap) Len() int { return len(h.heap) }
+func (h primHeap) Less(i, j int) bool {
+	return h.pool[h.heap[i]].w < h.pool[h.heap[j]].w
+}
+func (h primHeap) Swap(i, j int) {
+	h.heap[i], h.heap[j] = h.heap[j], h.heap[i]
+	h.pool[h.heap[i]].rx = i
+	h.pool[h.heap[j]].rx = j
+}
+func (h *primHeap) Push(x interface{}) {
+	tx := x.(int)
+	h.pool[tx].rx = len(h.heap)
+	h.heap = append(h.heap, tx)
+}
+func (h *primHeap) Pop() interface{} {
+	last := len(h.heap) - 1
+	x := h.heap[last]
+	h.heap = h.heap[:last]
+	return x
+}
@@ -0,0 +1,105 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/adj"
+)
+
+func mstGraph() adj.Graph {
+	g := adj.NewGraph()
+	g.Link("a", "b", adj.Weighted(7))
+	g.Link("a", "c", adj.Weighted(9))
+	g.Link("a", "f", adj.Weighted(14))
+	g.Link("b", "c", adj.Weighted(10))
+	g.Link("b", "d", adj.Weighted(15))
+	g.Link("c", "d", adj.Weighted(11))
+	g.Link("c", "f", adj.Weighted(2))
+	g.Link("d", "e", adj.Weighted(6))
+	g.Link("e", "f", adj.Weighted(9))
+	g.Link("x", "y", adj.Weighted(1)) // a second component
+	return g
+}
+
+// printForest prints edges in a repeatable order.
+func printForest(f adj.Graph, w float64) {
+	var s []string
+	for _, nd := range f.Nodes {
+		nd.VisitAdjHalfs(func(h graph2.Half) {
+			if n1, n2 := nd.String(), h.To.(*adj.Node).String(); n1 < n2 {
+				s = append(s, fmt.Sprint(n1, " ", n2, " ", h.Ed))
+			}
+		})
+	}
+	sort.Strings(s)
+	for _, e := range s {
+		fmt.Println(e)
+	}
+	fmt.Println(len(f.Nodes), "nodes, total weight", w)
+}
+
+func ExampleGraph_Kruskal() {
+	printForest(mstGraph().Kruskal())
+	// Output:
+	// a b 7
+	// a c 9
+	// c f 2
+	// d e 6
+	// e f 9
+	// x y 1
+	// 8 nodes, total weight 34
+}
+
+func ExampleGraph_Prim() {
+	printForest(mstGraph().Prim())
+	// Output:
+	// a b 7
+	// a c 9
+	// c f 2
+	// d e 6
+	// e f 9
+	// x y 1
+	// 8 nodes, total weight 34
+}
+
+func ExampleGraph_Boruvka() {
+	printForest(mstGraph().Boruvka())
+	// Output:
+	// a b 7
+	// a c 9
+	// c f 2
+	// d e 6
+	// e f 9
+	// x y 1
+	// 8 nodes, total weight 34
+}
+
+func TestMSTRandom(t *testing.T) {
+	// random graph with many equal weights
+	r := rand.New(rand.NewSource(1))
+	g := adj.NewGraph()
+	for i := 0; i < 2000; i++ {
+		g.Link(r.Intn(300), r.Intn(300), adj.Weighted(r.Intn(20)))
+	}
+	fk, wk := g.Kruskal()
+	fp, wp := g.Prim()
+	fb, wb := g.Boruvka()
+	if math.Abs(wk-wp) > 1e-9 || math.Abs(wk-wb) > 1e-9 {
+		t.Fatal("weights differ:", wk, wp, wb)
+	}
+	ne := len(fk.Edges)
+	if len(fp.Edges) != ne || len(fb.Edges) != ne {
+		t.Fatal("edge counts differ:", ne, len(fp.Edges), len(fb.Edges))
+	}
+	if len(fk.Nodes) != len(g.Nodes) {
+		t.Fatal("forest has", len(fk.Nodes), "nodes, graph has", len(g.Nodes))
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+)
+
+// MultiEdge is an edge of a Multigraph.  It wraps an edge value so that
+// parallel edges, even with equal values, each have their own identity.
+//
+// MultiEdge implements graph2.Weighted if Ed does.  It also implements
+// fmt.Stringer.
+type MultiEdge struct {
+	Ed graph2.Edge
+}
+
+// Weight returns the weight of e.Ed.  This panics if e.Ed does not
+// implement graph2.Weighted.
+func (e *MultiEdge) Weight() float64 { return e.Ed.(graph2.Weighted).Weight() }
+
+// String returns a string representation of e.Ed.
+func (e *MultiEdge) String() string { return fmt.Sprint(e.Ed) }
+
+// Multigraph represents an undirected graph that may have parallel edges.
+//
+// Edges holds, for each pair of adjacent nodes, the list of edges between
+// them.  Half edges in Nbs and In of nodes hold *MultiEdge values.  Searches
+// that relax each half edge, such as those of package graph/search, thus
+// consider each parallel edge and naturally find the best.  The *MultiEdge
+// in a path found identifies the particular edge used.
+type Multigraph struct {
+	Nodes map[interface{}]*Node
+	Edges map[struct{ n1, n2 *Node }][]*MultiEdge
+}
+
+func NewMultigraph() Multigraph {
+	return Multigraph{
+		Nodes: map[interface{}]*Node{},
+		Edges: map[struct{ n1, n2 *Node }][]*MultiEdge{},
+	}
+}
+
+// Link adds an edge between nodes with keys n1 and n2, adding either or
+// both nodes to the graph as neccessary.  Unlike Graph.Link, Link always
+// adds a new edge, even if there are already edges between n1 and n2.
+//
+// Node keys and Data are as documented for Digraph.Link.  Ed may be of any
+// type but if g will be used in a context that uses edges as weighted
+// edges, ed must implement graph2.Weighted.  Link returns the new edge.
+func (g Multigraph) Link(n1, n2 interface{}, ed graph2.Edge) *MultiEdge {
+	nd1, ok := g.Nodes[n1]
+	if !ok {
+		nd1 = &Node{Data: n1}
+		g.Nodes[n1] = nd1
+	}
+	nd2, ok := g.Nodes[n2]
+	if !ok {
+		nd2 = &Node{Data: n2}
+		g.Nodes[n2] = nd2
+	}
+	e := &MultiEdge{ed}
+	k := g.pairKey(nd1, nd2)
+	g.Edges[k] = append(g.Edges[k], e)
+	nd1.Nbs = append(nd1.Nbs, graph2.Half{e, nd2})
+	nd2.Nbs = append(nd2.Nbs, graph2.Half{e, nd1})
+	nd1.In = append(nd1.In, graph2.FromHalf{nd2, e})
+	nd2.In = append(nd2.In, graph2.FromHalf{nd1, e})
+	return e
+}
+
+// Between returns the edges between nodes with keys n1 and n2.
+func (g Multigraph) Between(n1, n2 interface{}) []*MultiEdge {
+	nd1, ok1 := g.Nodes[n1]
+	nd2, ok2 := g.Nodes[n2]
+	if !ok1 || !ok2 {
+		return nil
+	}
+	return g.Edges[g.pairKey(nd1, nd2)]
+}
+
+// NumEdges returns the number of edges in the graph, counting each
+// parallel edge.
+func (g Multigraph) NumEdges() int {
+	m := 0
+	for _, es := range g.Edges {
+		m += len(es)
+	}
+	return m
+}
+
+// Unlink removes all edges between nodes with keys n1 and n2.  Nodes are
+// not removed.  Unlink returns the number of edges removed.
+func (g Multigraph) Unlink(n1, n2 interface{}) int {
+	nd1, ok1 := g.Nodes[n1]
+	nd2, ok2 := g.Nodes[n2]
+	if !ok1 || !ok2 {
+		return 0
+	}
+	k := g.pairKey(nd1, nd2)
+	n := len(g.Edges[k])
+	delete(g.Edges, k)
+	unlinkNodes(nd1, nd2)
+	return n
+}
+
+// UnlinkEdge removes edge e between nodes with keys n1 and n2.  It returns
+// false if e was not an edge between n1 and n2.
+func (g Multigraph) UnlinkEdge(n1, n2 interface{}, e *MultiEdge) bool {
+	nd1, ok1 := g.Nodes[n1]
+	nd2, ok2 := g.Nodes[n2]
+	if !ok1 || !ok2 {
+		return false
+	}
+	k := g.pairKey(nd1, nd2)
+	es := g.Edges[k]
+	i := 0
+	for i < len(es) && es[i] != e {
+		i++
+	}
+	if i == len(es) {
+		return false
+	}
+	if len(es) == 1 {
+		delete(g.Edges, k)
+	} else {
+		g.Edges[k] = append(es[:i:i], es[i+1:]...)
+	}
+	for _, nd := range []*Node{nd1, nd2} {
+		nd.removeNbs(func(h graph2.Half) bool { return h.Ed == e }, 1)
+		nd.removeIn(func(h graph2.FromHalf) bool { return h.Ed == e }, 1)
+	}
+	return true
+}
+
+// RemoveNode removes the node with key n from the graph, along with all
+// edges incident to it.  It returns false if n was not in the graph.
+func (g Multigraph) RemoveNode(n interface{}) bool {
+	nd, ok := g.Nodes[n]
+	if !ok {
+		return false
+	}
+	for _, h := range nd.Nbs {
+		other := h.To.(*Node)
+		delete(g.Edges, g.pairKey(nd, other))
+		if other == nd {
+			continue // self loop
+		}
+		other.removeNbs(func(h graph2.Half) bool { return h.To == nd }, -1)
+		other.removeIn(func(h graph2.FromHalf) bool { return h.From == nd }, -1)
+	}
+	delete(g.Nodes, n)
+	return true
+}
+
+// pairKey returns the key in g.Edges for edges between n1 and n2.  The
+// key orientation is that of the first edge linked between the nodes.
+func (g Multigraph) pairKey(n1, n2 *Node) edgeKey {
+	k := edgeKey{n1, n2}
+	if _, ok := g.Edges[k]; !ok {
+		if r := (edgeKey{n2, n1}); len(g.Edges[r]) > 0 {
+			return r
+		}
+	}
+	return k
+}
@@ -0,0 +1,105 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph2/adj"
+	"github.com/soniakeys/graph2/search"
+)
+
+func ExampleMultigraph() {
+	g := adj.NewMultigraph()
+	g.Link("A", "B", adj.Weighted(10)) // slow line
+	fast := g.Link("A", "B", adj.Weighted(4))
+	g.Link("B", "C", adj.Weighted(3))
+	g.Link("C", "B", adj.Weighted(3)) // parallel, equal weight
+	fmt.Println(g.NumEdges(), "edges,", len(g.Between("B", "C")), "between B and C")
+	path, dist := search.DijkstraShortestPath(g.Nodes["A"], g.Nodes["C"])
+	fmt.Println(path, dist)
+	fmt.Println(path[1].Ed == fast)
+	// Output:
+	// 4 edges, 2 between B and C
+	// [{<nil> A} {4 B} {3 C}] 7
+	// true
+}
+
+func ExampleMultigraph_UnlinkEdge() {
+	g := adj.NewMultigraph()
+	e1 := g.Link(0, 1, "x")
+	g.Link(0, 1, "x")
+	fmt.Println(g.UnlinkEdge(1, 0, e1), g.UnlinkEdge(1, 0, e1))
+	fmt.Println(g.Nodes[0].Nbs, g.Nodes[1].Nbs, g.NumEdges())
+	// Output:
+	// true false
+	// [{x 1}] [{x 0}] 1
+}
+
+// checkMultigraph verifies that Edges, Nbs and In of g are consistent.
+func checkMultigraph(t *testing.T, g adj.Multigraph) {
+	half := 0
+	for _, nd := range g.Nodes {
+		if len(nd.Nbs) != len(nd.In) {
+			t.Fatal("Nbs and In differ for", nd)
+		}
+		for i, h := range nd.Nbs {
+			to := h.To.(*adj.Node)
+			if g.Nodes[to.Data] != to {
+				t.Fatal("edge to node not in graph", to)
+			}
+			if in := nd.In[i]; in.From.(*adj.Node) != to || in.Ed != h.Ed {
+				t.Fatal("In does not mirror Nbs for", nd)
+			}
+			found := false
+			for _, e := range g.Between(nd.Data, to.Data) {
+				if e == h.Ed {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatal("half edge not in Edges", nd, h)
+			}
+		}
+		half += len(nd.Nbs)
+	}
+	if half != 2*g.NumEdges() {
+		t.Fatal(half, "half edges for", g.NumEdges(), "edges")
+	}
+}
+
+func TestMultigraphRandom(t *testing.T) {
+	x := rand.New(rand.NewSource(19))
+	g := adj.NewMultigraph()
+	var edges []struct {
+		n1, n2 int
+		e      *adj.MultiEdge
+	}
+	const nn = 20
+	for i := 0; i < 2000; i++ {
+		n1, n2 := x.Intn(nn), x.Intn(nn)
+		switch x.Intn(6) {
+		case 0, 1, 2:
+			e := g.Link(n1, n2, adj.Weighted(x.Intn(3)))
+			edges = append(edges, struct {
+				n1, n2 int
+				e      *adj.MultiEdge
+			}{n1, n2, e})
+		case 3:
+			if len(edges) > 0 {
+				e := edges[x.Intn(len(edges))]
+				g.UnlinkEdge(e.n2, e.n1, e.e)
+			}
+		case 4:
+			g.Unlink(n1, n2)
+		case 5:
+			if x.Intn(4) == 0 {
+				g.RemoveNode(n1)
+			}
+		}
+		checkMultigraph(t, g)
+	}
+}
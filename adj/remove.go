@@ -0,0 +1,222 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj
+
+import (
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/internal"
+)
+
+// Unlink removes all arcs from the node with key n1 to the node with key
+// n2.  Nodes are not removed.  Unlink returns the number of arcs removed.
+func (g Digraph) Unlink(n1, n2 interface{}) int {
+	nd1, ok1 := g[n1]
+	nd2, ok2 := g[n2]
+	if !ok1 || !ok2 {
+		return 0
+	}
+	n := nd1.removeNbs(func(h graph2.Half) bool { return h.To == nd2 }, -1)
+	nd2.removeIn(func(h graph2.FromHalf) bool { return h.From == nd1 }, -1)
+	return n
+}
+
+// UnlinkArc removes a single arc from the node with key n1 to the node with
+// key n2.  Of parallel arcs, it removes the first one equal to arc.  Arcs
+// that are not comparable, or that hold values that are not comparable,
+// never compare equal.
+// UnlinkArc returns false if no such arc was found.
+func (g Digraph) UnlinkArc(n1, n2 interface{}, arc graph2.Arc) bool {
+	nd1, ok1 := g[n1]
+	nd2, ok2 := g[n2]
+	if !ok1 || !ok2 {
+		return false
+	}
+	if nd1.removeNbs(func(h graph2.Half) bool {
+		return h.To == nd2 && internal.Equal(h.Ed, arc)
+	}, 1) == 0 {
+		return false
+	}
+	nd2.removeIn(func(h graph2.FromHalf) bool {
+		return h.From == nd1 && internal.Equal(h.Ed, arc)
+	}, 1)
+	return true
+}
+
+// ReplaceArc replaces an arc from the node with key n1 to the node with key
+// n2.  Of parallel arcs, it replaces the first one equal to old, as
+// described for UnlinkArc.  The new arc keeps the position of the old one
+// in Nbs and In.  ReplaceArc returns false if no such arc was found.
+func (g Digraph) ReplaceArc(n1, n2 interface{}, old, arc graph2.Arc) bool {
+	nd1, ok1 := g[n1]
+	nd2, ok2 := g[n2]
+	if !ok1 || !ok2 {
+		return false
+	}
+	found := false
+	for i, h := range nd1.Nbs {
+		if h.To == nd2 && internal.Equal(h.Ed, old) {
+			nd1.Nbs[i].Ed = arc
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	for i, h := range nd2.In {
+		if h.From == nd1 && internal.Equal(h.Ed, old) {
+			nd2.In[i].Ed = arc
+			break
+		}
+	}
+	return true
+}
+
+// RemoveNode removes the node with key n from the graph, along with all
+// arcs leading from or to it.  It returns false if n was not in the graph.
+func (g Digraph) RemoveNode(n interface{}) bool {
+	nd, ok := g[n]
+	if !ok {
+		return false
+	}
+	for _, h := range nd.Nbs {
+		h.To.(*Node).removeIn(func(h graph2.FromHalf) bool {
+			return h.From == nd
+		}, -1)
+	}
+	for _, h := range nd.In {
+		h.From.(*Node).removeNbs(func(h graph2.Half) bool {
+			return h.To == nd
+		}, -1)
+	}
+	delete(g, n)
+	return true
+}
+
+// Unlink removes the edge between the nodes with keys n1 and n2.  Nodes
+// are not removed.  Unlink returns false if there was no such edge.
+func (g Graph) Unlink(n1, n2 interface{}) bool {
+	nd1, ok1 := g.Nodes[n1]
+	nd2, ok2 := g.Nodes[n2]
+	if !ok1 || !ok2 {
+		return false
+	}
+	k, ok := g.edgeBetween(nd1, nd2)
+	if !ok {
+		return false
+	}
+	delete(g.Edges, k)
+	unlinkNodes(nd1, nd2)
+	return true
+}
+
+// SetEdge replaces the value of the edge between the nodes with keys n1
+// and n2, keeping Edges, Nbs and In consistent.  It returns false if there
+// was no such edge.
+//
+// Graph.Link does not add parallel edges, so Edges holds at most one edge
+// between two nodes and SetEdge replaces all half edges between n1 and n2.
+// A Graph with parallel edges added to Nbs and In by other means will have
+// all of them replaced.  Use Multigraph for parallel edges.
+func (g Graph) SetEdge(n1, n2 interface{}, ed graph2.Edge) bool {
+	nd1, ok1 := g.Nodes[n1]
+	nd2, ok2 := g.Nodes[n2]
+	if !ok1 || !ok2 {
+		return false
+	}
+	k, ok := g.edgeBetween(nd1, nd2)
+	if !ok {
+		return false
+	}
+	g.Edges[k] = ed
+	for _, p := range [2][2]*Node{{nd1, nd2}, {nd2, nd1}} {
+		nd, other := p[0], p[1]
+		for i, h := range nd.Nbs {
+			if h.To == other {
+				nd.Nbs[i].Ed = ed
+			}
+		}
+		for i, h := range nd.In {
+			if h.From == other {
+				nd.In[i].Ed = ed
+			}
+		}
+	}
+	return true
+}
+
+// RemoveNode removes the node with key n from the graph, along with all
+// edges incident to it.  It returns false if n was not in the graph.
+func (g Graph) RemoveNode(n interface{}) bool {
+	nd, ok := g.Nodes[n]
+	if !ok {
+		return false
+	}
+	for _, h := range nd.Nbs {
+		other := h.To.(*Node)
+		if k, ok := g.edgeBetween(nd, other); ok {
+			delete(g.Edges, k)
+		}
+		if other == nd {
+			continue // self loop
+		}
+		other.removeNbs(func(h graph2.Half) bool { return h.To == nd }, -1)
+		other.removeIn(func(h graph2.FromHalf) bool { return h.From == nd }, -1)
+	}
+	delete(g.Nodes, n)
+	return true
+}
+
+// edgeBetween returns the key in g.Edges of the edge between n1 and n2.
+func (g Graph) edgeBetween(n1, n2 *Node) (k edgeKey, ok bool) {
+	k = g.findKey(n1, n2)
+	_, ok = g.Edges[k]
+	return
+}
+
+// unlinkNodes removes half edges between nd1 and nd2 from both nodes.
+func unlinkNodes(nd1, nd2 *Node) {
+	nd1.removeNbs(func(h graph2.Half) bool { return h.To == nd2 }, -1)
+	nd1.removeIn(func(h graph2.FromHalf) bool { return h.From == nd2 }, -1)
+	nd2.removeNbs(func(h graph2.Half) bool { return h.To == nd1 }, -1)
+	nd2.removeIn(func(h graph2.FromHalf) bool { return h.From == nd1 }, -1)
+}
+
+// removeNbs removes up to max half arcs from n.Nbs for which f returns
+// true, or all of them if max is negative.  It preserves the order of
+// remaining half arcs and returns the number removed.
+func (n *Node) removeNbs(f func(graph2.Half) bool, max int) int {
+	r := 0
+	nbs := n.Nbs[:0]
+	for _, h := range n.Nbs {
+		if r != max && f(h) {
+			r++
+			continue
+		}
+		nbs = append(nbs, h)
+	}
+	for i := len(nbs); i < len(n.Nbs); i++ {
+		n.Nbs[i] = graph2.Half{} // release references
+	}
+	n.Nbs = nbs
+	return r
+}
+
+// removeIn is removeNbs for n.In.
+func (n *Node) removeIn(f func(graph2.FromHalf) bool, max int) int {
+	r := 0
+	in := n.In[:0]
+	for _, h := range n.In {
+		if r != max && f(h) {
+			r++
+			continue
+		}
+		in = append(in, h)
+	}
+	for i := len(in); i < len(n.In); i++ {
+		n.In[i] = graph2.FromHalf{}
+	}
+	n.In = in
+	return r
+}
@@ -0,0 +1,211 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package adj_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph2/adj"
+)
+
+func ExampleDigraph_UnlinkArc() {
+	g := adj.Digraph{}
+	g.Link(0, 1, "a")
+	g.Link(0, 1, "b") // parallel arc
+	g.Link(0, 1, "a") // and another with the same value
+	fmt.Println(g.UnlinkArc(0, 1, "a"))
+	fmt.Println(g[0].Nbs)
+	fmt.Println(g[1].In)
+	// Output:
+	// true
+	// [{b 1} {a 1}]
+	// [{0 b} {0 a}]
+}
+
+func ExampleDigraph_RemoveNode() {
+	g := adj.Digraph{}
+	g.Link(0, 1, nil)
+	g.Link(1, 2, nil)
+	g.Link(2, 0, nil)
+	g.RemoveNode(1)
+	fmt.Println(len(g), g[0].Nbs, g[2].In)
+	// Output:
+	// 2 [] []
+}
+
+func ExampleGraph_SetEdge() {
+	g := adj.NewGraph()
+	g.Link(0, 1, adj.Weighted(3))
+	g.SetEdge(1, 0, adj.Weighted(5))
+	fmt.Println(g.Nodes[0].Nbs, g.Nodes[1].Nbs)
+	for _, ed := range g.Edges {
+		fmt.Println(ed)
+	}
+	// Output:
+	// [{5 1}] [{5 0}]
+	// 5
+}
+
+func ExampleGraph_Unlink() {
+	g := adj.NewGraph()
+	g.Link(0, 1, nil)
+	g.Link(1, 2, nil)
+	fmt.Println(g.Connected(0, 2))
+	g.Unlink(2, 1)
+	fmt.Println(g.Connected(0, 2), len(g.Edges), len(g.Nodes))
+	// Output:
+	// true
+	// false 1 3
+}
+
+func TestUnlinkArcNotComparable(t *testing.T) {
+	// arcs that are not comparable never compare equal, and must not panic
+	type tagged struct{ tag interface{} }
+	for _, arc := range []interface{}{[]int{1}, tagged{[]int{1}}} {
+		g := adj.Digraph{}
+		g.Link(0, 1, arc)
+		if g.UnlinkArc(0, 1, arc) || g.ReplaceArc(0, 1, arc, nil) {
+			t.Fatalf("%#v matched", arc)
+		}
+		if len(g[0].Nbs) != 1 || len(g[1].In) != 1 {
+			t.Fatalf("%#v removed", arc)
+		}
+	}
+}
+
+// checkDigraph verifies that Nbs and In of all nodes of g mirror each other
+// and that arcs lead to nodes of g keyed by their Data.
+func checkDigraph(t *testing.T, g adj.Digraph) {
+	checkArcs(t, g, func(nd *adj.Node) bool { return g[nd.Data] == nd })
+}
+
+// checkReadDigraph is checkDigraph for graphs read by functions such as
+// ReadGraphMLDigraph and ReadDIMACS, where node Data need not be the node
+// key.
+func checkReadDigraph(t *testing.T, g adj.Digraph) {
+	in := map[*adj.Node]bool{}
+	for _, nd := range g {
+		in[nd] = true
+	}
+	checkArcs(t, g, func(nd *adj.Node) bool { return in[nd] })
+}
+
+// checkArcs verifies that Nbs and In of all nodes of g mirror each other
+// and that arcs lead to nodes for which inG returns true.
+func checkArcs(t *testing.T, g adj.Digraph, inG func(*adj.Node) bool) {
+	type arc struct {
+		from, to *adj.Node
+		ed       interface{}
+	}
+	out := map[arc]int{}
+	for _, nd := range g {
+		for _, h := range nd.Nbs {
+			to := h.To.(*adj.Node)
+			if !inG(to) {
+				t.Fatal("arc to node not in graph", to)
+			}
+			out[arc{nd, to, h.Ed}]++
+		}
+	}
+	for _, nd := range g {
+		for _, h := range nd.In {
+			a := arc{h.From.(*adj.Node), nd, h.Ed}
+			if out[a] == 0 {
+				t.Fatal("In has arc not in Nbs", a)
+			}
+			out[a]--
+		}
+	}
+	for a, n := range out {
+		if n != 0 {
+			t.Fatal("Nbs has arc not in In", a)
+		}
+	}
+}
+
+// checkGraph verifies that Edges, Nbs and In of g are consistent and that
+// edges lead to nodes of g keyed by their Data.
+func checkGraph(t *testing.T, g adj.Graph) {
+	checkEdges(t, g, func(nd *adj.Node) bool { return g.Nodes[nd.Data] == nd })
+}
+
+// checkReadGraph is checkGraph for graphs read by functions such as
+// ReadGraphMLGraph, where node Data need not be the node key.
+func checkReadGraph(t *testing.T, g adj.Graph) {
+	in := map[*adj.Node]bool{}
+	for _, nd := range g.Nodes {
+		in[nd] = true
+	}
+	checkEdges(t, g, func(nd *adj.Node) bool { return in[nd] })
+}
+
+// checkEdges verifies that Edges, Nbs and In of g are consistent and that
+// edges lead to nodes for which inG returns true.
+func checkEdges(t *testing.T, g adj.Graph, inG func(*adj.Node) bool) {
+	half := 0
+	for _, nd := range g.Nodes {
+		if len(nd.Nbs) != len(nd.In) {
+			t.Fatal("Nbs and In differ for", nd)
+		}
+		for i, h := range nd.Nbs {
+			to := h.To.(*adj.Node)
+			if !inG(to) {
+				t.Fatal("edge to node not in graph", to)
+			}
+			if in := nd.In[i]; in.From.(*adj.Node) != to || in.Ed != h.Ed {
+				t.Fatal("In does not mirror Nbs for", nd)
+			}
+		}
+		half += len(nd.Nbs)
+	}
+	if half != 2*len(g.Edges) {
+		t.Fatal(half, "half edges for", len(g.Edges), "edges")
+	}
+}
+
+func TestRemoveRandom(t *testing.T) {
+	x := rand.New(rand.NewSource(17))
+	d := adj.Digraph{}
+	g := adj.TrackComponents(adj.NewGraph())
+	const nn = 40
+	for i := 0; i < 2000; i++ {
+		n1, n2 := x.Intn(nn), x.Intn(nn)
+		switch x.Intn(6) {
+		case 0, 1, 2:
+			d.Link(n1, n2, x.Intn(3))
+			g.Link(n1, n2, x.Intn(3))
+		case 3:
+			d.Unlink(n1, n2)
+			g.Unlink(n1, n2)
+		case 4:
+			d.UnlinkArc(n1, n2, x.Intn(3))
+			d.ReplaceArc(n2, n1, x.Intn(3), x.Intn(3))
+			g.SetEdge(n1, n2, x.Intn(3))
+		case 5:
+			if x.Intn(4) == 0 {
+				d.RemoveNode(n1)
+				g.RemoveNode(n1)
+			}
+		}
+		checkDigraph(t, d)
+		checkGraph(t, g.Graph)
+		if i%100 == 0 && fmt.Sprint(sortedComponents(g.Components())) !=
+			fmt.Sprint(sortedComponents(g.Graph.Components())) {
+			t.Fatal("tracked and searched components differ")
+		}
+	}
+	// tracked components still agree with a search
+	for a := 0; a < nn; a++ {
+		for b := 0; b < nn; b++ {
+			if _, ok := g.Nodes[a]; !ok {
+				continue
+			}
+			if g.Connected(a, b) != g.Graph.Connected(a, b) {
+				t.Fatal("tracked Connected wrong for", a, b)
+			}
+		}
+	}
+}
@@ -153,3 +153,118 @@ func ExampleDigraph_dijkstraAllPaths() {
 	// a 9 c 11 d 6 e
 	// a 9 c 2 f
 }
+
+func ExampleDigraph_dijkstraBidirectional() {
+	g := adj.Digraph{}
+	g.Link("a", "b", adj.Weighted(7))
+	g.Link("a", "c", adj.Weighted(9))
+	g.Link("a", "f", adj.Weighted(14))
+	g.Link("b", "c", adj.Weighted(10))
+	g.Link("b", "d", adj.Weighted(15))
+	g.Link("c", "d", adj.Weighted(11))
+	g.Link("c", "f", adj.Weighted(2))
+	g.Link("d", "e", adj.Weighted(6))
+	g.Link("e", "f", adj.Weighted(9))
+	// run bidirectional search, which uses both Nbs and In of the nodes.
+	path, l := search.DijkstraBidirectional(g["a"], g["e"])
+	fmt.Println(`Shortest path from node "a" to node "e":`, path)
+	fmt.Println("Path length:", l)
+	// Output:
+	// Shortest path from node "a" to node "e": [{<nil> a} {9 c} {11 d} {6 e}]
+	// Path length: 26
+}
+
+func ExampleGraph_dijkstraBidirectional() {
+	g := adj.NewGraph()
+	g.Link("a", "b", adj.Weighted(7))
+	g.Link("a", "c", adj.Weighted(9))
+	g.Link("a", "f", adj.Weighted(14))
+	g.Link("b", "c", adj.Weighted(10))
+	g.Link("b", "d", adj.Weighted(15))
+	g.Link("c", "d", adj.Weighted(11))
+	g.Link("c", "f", adj.Weighted(2))
+	g.Link("d", "e", adj.Weighted(6))
+	g.Link("e", "f", adj.Weighted(9))
+	path, l := search.DijkstraBidirectional(g.Nodes["a"], g.Nodes["e"])
+	fmt.Println("Shortest path:", path)
+	fmt.Println("Path length:", l)
+	// Output:
+	// Shortest path: [{<nil> a} {9 c} {2 f} {9 e}]
+	// Path length: 20
+}
+
+func ExampleGraph_breadthFirst2() {
+	g := adj.NewGraph()
+	for _, e := range [][2]int{
+		{1, 3}, {3, 5}, {2, 5}, {4, 5}, {6, 5}, {9, 5}, {10, 5}, {11, 5},
+		{12, 5}, {12, 13}, {11, 14}, {11, 15}, {14, 15}, {26, 15}, {26, 5},
+		{26, 30}, {26, 29}, {26, 28}, {26, 27}, {26, 16}, {10, 16}, {17, 16},
+		{17, 10}, {17, 9}, {19, 9}, {19, 5}, {19, 8}, {7, 8}, {19, 18},
+		{19, 20}, {19, 22}, {19, 21}, {25, 21}, {24, 21}, {23, 21}, {24, 31},
+		{25, 32}, {33, 32},
+	} {
+		g.Link(e[0], e[1], nil)
+	}
+	// list paths up to two levels from node 17
+	v := func(n graph2.BF2Node, level int) bool {
+		return level <= 2
+	}
+	start := g.Nodes[17]
+	p, _ := search.BreadthFirst2(g.BF2Graph(), start, v)
+	var paths []string
+	for n1 := range p {
+		s := fmt.Sprint(n1)
+		for n0 := p[n1]; n0 != nil; n0 = p[n0] {
+			s = fmt.Sprintf("%s %s", n0, s)
+		}
+		paths = append(paths, s)
+	}
+	// sort for test repeatability
+	sort.Strings(paths)
+	for _, s := range paths {
+		fmt.Println(s)
+	}
+	// Output:
+	// 17
+	// 17 10
+	// 17 16
+	// 17 16 26
+	// 17 9
+	// 17 9 19
+	// 17 9 5
+}
+
+func ExampleDigraph_breadthFirst2() {
+	g := adj.Digraph{}
+	g.Link(5, 6, nil)
+	g.Link(5, 7, nil)
+	g.Link(5, 9, nil)
+	g.Link(6, 7, nil)
+	g.Link(7, 5, nil)
+	g.Link(7, 7, nil)
+	g.Link(7, 8, nil)
+	g.Link(9, 8, nil)
+	var visited []string
+	v := func(n graph2.BF2Node, level int) bool {
+		visited = append(visited, fmt.Sprint(n, " ", level))
+		return true
+	}
+	_, ok := search.BreadthFirst2(g, g[5], v)
+	fmt.Println(ok)
+	fmt.Println(g.NumEdges(), "arcs")
+	// sort for test repeatability
+	sort.Strings(visited)
+	fmt.Println("Node  Level")
+	for _, s := range visited {
+		fmt.Println(s)
+	}
+	// Output:
+	// true
+	// 8 arcs
+	// Node  Level
+	// 5 0
+	// 6 1
+	// 7 1
+	// 8 2
+	// 9 1
+}
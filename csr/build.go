@@ -0,0 +1,122 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package csr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/adj"
+)
+
+// Builder accumulates arcs for constructing a Graph.
+type Builder[W Weight] struct {
+	n       int
+	from    []int32
+	to      []int32
+	weights []W
+}
+
+// NewBuilder returns a Builder for a graph of numNodes nodes.
+//
+// NewBuilder panics if numNodes is negative or more than math.MaxInt32.
+func NewBuilder[W Weight](numNodes int) *Builder[W] {
+	if numNodes < 0 || numNodes > math.MaxInt32 {
+		panic(fmt.Sprint("csr.NewBuilder: invalid number of nodes ", numNodes))
+	}
+	return &Builder[W]{n: numNodes}
+}
+
+// AddArc adds an arc from node index from to node index to with weight w.
+//
+// AddArc panics if either index is out of range.
+func (b *Builder[W]) AddArc(from, to int, w W) {
+	if from < 0 || from >= b.n || to < 0 || to >= b.n {
+		panic(fmt.Sprintf("csr.Builder.AddArc: arc %d->%d out of range", from, to))
+	}
+	b.from = append(b.from, int32(from))
+	b.to = append(b.to, int32(to))
+	b.weights = append(b.weights, w)
+}
+
+// Build returns the Graph of arcs added to b.  Arcs from each node are
+// stored in the order they were added.  The Builder is reset to empty with
+// the same number of nodes.
+func (b *Builder[W]) Build() *Graph[W] {
+	g := &Graph[W]{
+		off:     make([]int, b.n+1),
+		targets: make([]int32, len(b.from)),
+		weights: make([]W, len(b.from)),
+	}
+	// counting sort by from node
+	for _, f := range b.from {
+		g.off[f+1]++
+	}
+	for i := 1; i <= b.n; i++ {
+		g.off[i] += g.off[i-1]
+	}
+	next := make([]int, b.n)
+	copy(next, g.off)
+	for a, f := range b.from {
+		x := next[f]
+		next[f]++
+		g.targets[x] = b.to[a]
+		g.weights[x] = b.weights[a]
+	}
+	b.from, b.to, b.weights = nil, nil, nil
+	return g
+}
+
+// FromDigraph returns a Graph with the nodes and arcs of g, and the keys
+// of g by node index.
+//
+// If all keys of g are ints or all are strings, nodes are indexed in order
+// of their keys.  Otherwise the node order is unspecified.  Arcs from each
+// node are in the order of Node.Nbs.  Arcs implementing graph2.Weighted
+// take their weights, converted to W.  Other arcs have weight 1.
+func FromDigraph[W Weight](g adj.Digraph) (*Graph[W], []interface{}) {
+	keys := make([]interface{}, 0, len(g))
+	for k := range g {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+	x := make(map[*adj.Node]int, len(keys))
+	for i, k := range keys {
+		x[g[k]] = i
+	}
+	b := NewBuilder[W](len(keys))
+	for i, k := range keys {
+		for _, h := range g[k].Nbs {
+			w := W(1)
+			if wt, ok := h.Ed.(graph2.Weighted); ok {
+				w = W(wt.Weight())
+			}
+			b.AddArc(i, x[h.To.(*adj.Node)], w)
+		}
+	}
+	return b.Build(), keys
+}
+
+// sortKeys sorts keys if they are all ints or all strings.
+func sortKeys(keys []interface{}) {
+	ints, strs := true, true
+	for _, k := range keys {
+		switch k.(type) {
+		case int:
+			strs = false
+		case string:
+			ints = false
+		default:
+			return
+		}
+	}
+	switch {
+	case ints:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].(int) < keys[j].(int) })
+	case strs:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].(string) < keys[j].(string) })
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+// Package csr implements a compact, immutable directed graph in compressed
+// sparse row form.
+//
+// Nodes are indexed 0 through NumNodes-1.  Arcs from node i are stored
+// contiguously, their targets in a []int32 and their weights in a []W where
+// W is float32 or float64.  An offset per node locates its arcs.  A graph
+// with float32 weights costs 8 bytes per arc and 8 bytes per node.
+//
+// Node handles, of type Node, are small values that implement both the
+// interface{} based and the type parameterized node interfaces of package
+// graph2, so a Graph can be searched with functions of package graph/search.
+// The type parameterized functions, those with names ending in "Of", avoid
+// allocation for each arc visited and are much faster.
+//
+// A Graph is constructed with a Builder or from an adj.Digraph with
+// FromDigraph.
+package csr
+
+import (
+	"strconv"
+
+	"github.com/soniakeys/graph2"
+)
+
+// Weight is the constraint for arc weight types.
+type Weight interface {
+	float32 | float64
+}
+
+// Graph is an immutable directed graph in compressed sparse row form.
+type Graph[W Weight] struct {
+	off     []int   // arcs of node i are at off[i]:off[i+1]
+	targets []int32 // arc targets, by arc index
+	weights []W     // arc weights, by arc index
+}
+
+// NumNodes returns the number of nodes in g.
+func (g *Graph[W]) NumNodes() int { return len(g.off) - 1 }
+
+// NumArcs returns the number of arcs in g.
+func (g *Graph[W]) NumArcs() int { return len(g.targets) }
+
+// Node returns a handle for node i.
+func (g *Graph[W]) Node(i int) Node[W] { return Node[W]{g, int32(i)} }
+
+// Out returns the targets and weights of arcs from node i.  The slices
+// share storage with g and must not be modified.
+func (g *Graph[W]) Out(i int) (targets []int32, weights []W) {
+	s, e := g.off[i], g.off[i+1]
+	return g.targets[s:e:e], g.weights[s:e:e]
+}
+
+// Arc is the arc type of a Graph.  It implements graph2.Weighted.
+type Arc[W Weight] struct {
+	I int // arc index in the graph
+	W W   // arc weight
+}
+
+// Weight returns the arc weight as a float64.
+func (a Arc[W]) Weight() float64 { return float64(a.W) }
+
+// String returns a string representation of the arc weight.
+func (a Arc[W]) String() string {
+	return strconv.FormatFloat(float64(a.W), 'g', -1, 64)
+}
+
+// Node is a handle for a node of a Graph.
+//
+// Node values are comparable and identify the same node if they are equal.
+// Node implements graph2.HalfNode, graph2.Node and graph2.IndexedNode, and
+// satisfies graph2.HalfNodeOf[Node[W], Arc[W]] and graph2.NodeOf[Node[W]].
+type Node[W Weight] struct {
+	g *Graph[W]
+	i int32
+}
+
+// Index returns the node index.
+func (n Node[W]) Index() int { return int(n.i) }
+
+// Graph returns the graph containing n.
+func (n Node[W]) Graph() *Graph[W] { return n.g }
+
+// NodeID implements graph2.IndexedNode.  It returns the node index.
+func (n Node[W]) NodeID() int { return int(n.i) }
+
+// NumNodes implements graph2.IndexedNode.  It returns the number of nodes
+// in the graph of n.
+func (n Node[W]) NumNodes() int { return n.g.NumNodes() }
+
+// NumAdj returns the number of arcs from n.
+func (n Node[W]) NumAdj() int { return n.g.off[n.i+1] - n.g.off[n.i] }
+
+// String returns the node index as a string.
+func (n Node[W]) String() string { return strconv.Itoa(int(n.i)) }
+
+// VisitAdjHalfs implements graph2.HalfNode.  Half values hold Arc[W] arcs
+// and Node[W] nodes.
+func (n Node[W]) VisitAdjHalfs(v graph2.AdjHalfVisitor) {
+	g := n.g
+	for a, e := g.off[n.i], g.off[n.i+1]; a < e; a++ {
+		v(graph2.Half{Arc[W]{a, g.weights[a]}, Node[W]{g, g.targets[a]}})
+	}
+}
+
+// VisitAdjHalfsOf satisfies graph2.HalfNodeOf[Node[W], Arc[W]].
+func (n Node[W]) VisitAdjHalfsOf(v graph2.AdjHalfVisitorOf[Node[W], Arc[W]]) {
+	g := n.g
+	for a, e := g.off[n.i], g.off[n.i+1]; a < e; a++ {
+		v(graph2.HalfOf[Node[W], Arc[W]]{Arc[W]{a, g.weights[a]}, Node[W]{g, g.targets[a]}})
+	}
+}
+
+// VisitAdjNodes implements graph2.Node.
+func (n Node[W]) VisitAdjNodes(v graph2.AdjNodeVisitor) bool {
+	g := n.g
+	for _, t := range g.targets[g.off[n.i]:g.off[n.i+1]] {
+		if !v(Node[W]{g, t}) {
+			return false
+		}
+	}
+	return true
+}
+
+// VisitAdjNodesOf satisfies graph2.NodeOf[Node[W]].
+func (n Node[W]) VisitAdjNodesOf(v graph2.AdjNodeVisitorOf[Node[W]]) bool {
+	g := n.g
+	for _, t := range g.targets[g.off[n.i]:g.off[n.i+1]] {
+		if !v(Node[W]{g, t}) {
+			return false
+		}
+	}
+	return true
+}
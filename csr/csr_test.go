@@ -0,0 +1,140 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package csr_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/adj"
+	"github.com/soniakeys/graph2/csr"
+	"github.com/soniakeys/graph2/search"
+)
+
+var (
+	_ graph2.HalfNode = csr.Node[float32]{}
+	_ graph2.Node     = csr.Node[float64]{}
+	_ graph2.Weighted = csr.Arc[float32]{}
+)
+
+func ExampleFromDigraph() {
+	g := adj.Digraph{}
+	g.Link("a", "b", adj.Weighted(7))
+	g.Link("a", "c", adj.Weighted(9))
+	g.Link("b", "c", adj.Weighted(1))
+	g.Link("c", "d", nil)
+	c, keys := csr.FromDigraph[float32](g)
+	fmt.Println(c.NumNodes(), "nodes", c.NumArcs(), "arcs")
+	fmt.Println(keys)
+	path, dist := search.DijkstraShortestPathOf(c.Node(0), c.Node(3))
+	for _, h := range path {
+		fmt.Print(keys[h.To.Index()], " ")
+	}
+	fmt.Println(dist)
+	// Output:
+	// 4 nodes 4 arcs
+	// [a b c d]
+	// a b c d 9
+}
+
+func ExampleBuilder() {
+	b := csr.NewBuilder[float64](3)
+	b.AddArc(0, 1, 2.5)
+	b.AddArc(1, 2, 1)
+	b.AddArc(0, 2, 4)
+	g := b.Build()
+	fmt.Println(g.Out(0))
+	fmt.Println(search.DijkstraShortestPath(g.Node(0), g.Node(2)))
+	// Output:
+	// [1 2] [2.5 4]
+	// [{<nil> 0} {2.5 1} {1 2}] 3.5
+}
+
+// randDigraph returns a random adj.Digraph with int keys 0..n-1.
+func randDigraph(r *rand.Rand, n, m int) adj.Digraph {
+	g := adj.Digraph{}
+	for i := 0; i < n; i++ {
+		g[i] = &adj.Node{Data: i}
+	}
+	for i := 0; i < m; i++ {
+		g.Link(r.Intn(n), r.Intn(n), adj.Weighted(r.Intn(20)))
+	}
+	return g
+}
+
+func TestFromDigraph(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	g := randDigraph(r, 200, 1000)
+	c, keys := csr.FromDigraph[float64](g)
+	if c.NumNodes() != len(g) || c.NumArcs() != g.NumEdges() {
+		t.Fatalf("got %d nodes, %d arcs", c.NumNodes(), c.NumArcs())
+	}
+	for i, k := range keys {
+		if k != i {
+			t.Fatalf("key %d = %v", i, k)
+		}
+		n := c.Node(i)
+		nbs := g[k].Nbs
+		if n.NumAdj() != len(nbs) || n.Index() != i || n.Graph() != c {
+			t.Fatalf("node %d: %d arcs, want %d", i, n.NumAdj(), len(nbs))
+		}
+		x := 0
+		n.VisitAdjHalfs(func(h graph2.Half) {
+			if h.To.(csr.Node[float64]).Index() != nbs[x].To.(*adj.Node).Data ||
+				h.Ed.(csr.Arc[float64]).Weight() != nbs[x].Ed.(adj.Weighted).Weight() {
+				t.Fatalf("node %d arc %d: %v, want %v", i, x, h, nbs[x])
+			}
+			x++
+		})
+	}
+	for i := 0; i < 50; i++ {
+		s, e := r.Intn(len(g)), r.Intn(len(g))
+		_, want := search.DijkstraShortestPath(g[s], g[e])
+		_, d := search.DijkstraShortestPath(c.Node(s), c.Node(e))
+		_, dOf := search.DijkstraShortestPathOf(c.Node(s), c.Node(e))
+		if d != want || dOf != want {
+			t.Fatalf("%d->%d: distances %g, %g, want %g", s, e, d, dOf, want)
+		}
+	}
+	visited := 0
+	search.DepthFirstOf(c.Node(0), func(csr.Node[float64], int) bool {
+		visited++
+		return true
+	})
+	search.DepthFirst(c.Node(0), func(graph2.Node, int) bool {
+		visited--
+		return true
+	})
+	if visited != 0 {
+		t.Fatal("DepthFirst and DepthFirstOf visit different nodes")
+	}
+}
+
+func TestBuilderPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic for arc out of range")
+		}
+	}()
+	csr.NewBuilder[float32](2).AddArc(0, 2, 1)
+}
+
+func BenchmarkDijkstraAdj(b *testing.B) {
+	g := randDigraph(rand.New(rand.NewSource(1)), 10000, 50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		search.DijkstraAllPaths(g[0])
+	}
+}
+
+func BenchmarkDijkstraCSROf(b *testing.B) {
+	c, _ := csr.FromDigraph[float32](
+		randDigraph(rand.New(rand.NewSource(1)), 10000, 50000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		search.DijkstraAllPathsOf(c.Node(0))
+	}
+}
@@ -4,6 +4,10 @@
 // . Graph2 defines interfaces and other types useful for graph algorithms.
 // This package was exploratory and is no longer under development.
 //
+// Types with names ending in "Of", such as HalfOf and HalfNodeOf, are type
+// parameterized parallels of the interface{} based types.  They allow
+// graph algorithms to be checked at compile time for node and edge types.
+//
 // Subdirectory search contains graph search functions.  Implemented search
 // algorithms are Dijkstra’s shortest path, A*, and algorithm A.  Functions
 // in package search operate through the interfaces in package graph and make
@@ -13,5 +17,12 @@
 // The types are adequate for exercising the functions in package search and
 // are generalized to be useful for other applications.
 //
+// Subdirectory csr contains a compact, immutable graph in compressed sparse
+// row form for graphs too large for adj.  Its node handles implement the
+// interfaces of graph2 so that functions of search can operate on it.
+//
+// Subdirectory flow contains maximum flow and minimum cut functions.  Like
+// the functions of search, they operate through the interfaces of graph2.
+//
 // Neither search nor adj depend on the other; they only depend on graph.
 package graph2
@@ -0,0 +1,266 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+// Flow implements maximum flow algorithms, Dinic's algorithm and
+// push-relabel.
+//
+// The functions find a maximum flow from a source node to a sink node of
+// a directed graph, along with a minimum cut separating them.  They operate
+// on graph2.HalfNode graphs where arcs implement graph2.Capacity.
+// Capacities must be non-negative and must not be an Inf or NaN.
+//
+// Flow on each arc is reported in a map keyed by the arc objects found in
+// graph2.Half.Ed.  Arcs must therefore be comparable and, to be reported
+// individually, distinct.  A pointer type for arcs is one good choice.
+// If the same arc object appears more than once in the graph, the flows of
+// all of its appearances are summed.
+package flow
+
+import (
+	"math"
+
+	"github.com/soniakeys/graph2"
+)
+
+// Dinic finds a maximum flow from s to t using Dinic's algorithm.
+//
+// Returned are the value of the flow, the flow along each arc reachable
+// from s, and the source side of a minimum s-t cut.  The cut lists nodes
+// reachable from s in the residual network, starting with s.  Arcs from
+// cut nodes to nodes not in the cut are saturated and their capacities sum
+// to the flow value.
+//
+// If s and t are the same node, the flow value is 0 and the arc flow and
+// cut results are nil.
+func Dinic(s, t graph2.HalfNode) (value float64, arcFlow map[graph2.Arc]float64, cut []graph2.HalfNode) {
+	if s == t {
+		return 0, nil, nil
+	}
+	n := newNetwork(s)
+	if tx, ok := n.x[t]; ok {
+		value = n.dinic(0, tx)
+	}
+	return value, n.arcFlow(), n.cut()
+}
+
+// PushRelabel finds a maximum flow from s to t using the FIFO push-relabel
+// algorithm of Goldberg and Tarjan.
+//
+// Results are as documented for Dinic.
+func PushRelabel(s, t graph2.HalfNode) (value float64, arcFlow map[graph2.Arc]float64, cut []graph2.HalfNode) {
+	if s == t {
+		return 0, nil, nil
+	}
+	n := newNetwork(s)
+	if tx, ok := n.x[t]; ok {
+		value = n.pushRelabel(0, tx)
+	}
+	return value, n.arcFlow(), n.cut()
+}
+
+// network is a residual network with nodes and arcs indexed by integers.
+//
+// Residual arcs are allocated in pairs.  Arc 2k is the forward arc for
+// arc k of the original graph, arc 2k+1 is the reverse arc.  Thus the
+// partner of residual arc a is a^1.
+type network struct {
+	nodes []graph2.HalfNode       // node for each node index
+	x     map[graph2.HalfNode]int // node index for each node
+	adj   [][]int                 // residual arcs leaving each node
+	to    []int                   // node each residual arc leads to
+	cap   []float64               // residual capacity
+	arcs  []graph2.Arc            // original arc objects
+}
+
+// newNetwork builds a residual network of the nodes and arcs reachable
+// from s.  s is given index 0.
+func newNetwork(s graph2.HalfNode) *network {
+	n := &network{x: map[graph2.HalfNode]int{}}
+	n.node(s)
+	for u := 0; u < len(n.nodes); u++ {
+		n.nodes[u].VisitAdjHalfs(func(h graph2.Half) {
+			v := n.node(h.To)
+			a := len(n.to)
+			n.to = append(n.to, v, u)
+			n.cap = append(n.cap, h.Ed.(graph2.Capacity).Capacity(), 0)
+			n.adj[u] = append(n.adj[u], a)
+			n.adj[v] = append(n.adj[v], a+1)
+			n.arcs = append(n.arcs, h.Ed)
+		})
+	}
+	return n
+}
+
+// node returns the index of nd, adding it to the network as needed.
+func (n *network) node(nd graph2.HalfNode) int {
+	if x, ok := n.x[nd]; ok {
+		return x
+	}
+	x := len(n.nodes)
+	n.x[nd] = x
+	n.nodes = append(n.nodes, nd)
+	n.adj = append(n.adj, nil)
+	return x
+}
+
+// arcFlow returns the flow on each original arc.  The flow on an arc is
+// the residual capacity of its reverse arc.
+func (n *network) arcFlow() map[graph2.Arc]float64 {
+	f := make(map[graph2.Arc]float64, len(n.arcs))
+	for k, a := range n.arcs {
+		f[a] += n.cap[2*k+1]
+	}
+	return f
+}
+
+// cut returns nodes reachable from node 0 in the residual network.
+func (n *network) cut() []graph2.HalfNode {
+	seen := make([]bool, len(n.nodes))
+	seen[0] = true
+	q := []int{0}
+	for i := 0; i < len(q); i++ {
+		for _, a := range n.adj[q[i]] {
+			if v := n.to[a]; n.cap[a] > 0 && !seen[v] {
+				seen[v] = true
+				q = append(q, v)
+			}
+		}
+	}
+	cut := make([]graph2.HalfNode, len(q))
+	for i, u := range q {
+		cut[i] = n.nodes[u]
+	}
+	return cut
+}
+
+// dinic computes maximum flow from s to t, leaving the flow as residual
+// capacities.
+func (n *network) dinic(s, t int) (total float64) {
+	level := make([]int, len(n.nodes))
+	it := make([]int, len(n.nodes)) // current arc of each node
+	var path []int                  // residual arcs from s
+	for n.levels(s, t, level) {
+		for i := range it {
+			it[i] = 0
+		}
+		// find blocking flow by repeated search for augmenting paths
+		path = path[:0]
+		u := s
+		for {
+			if u == t {
+				f := math.Inf(1)
+				for _, a := range path {
+					f = math.Min(f, n.cap[a])
+				}
+				for _, a := range path {
+					n.cap[a] -= f
+					n.cap[a^1] += f
+				}
+				total += f
+				// retreat to the tail of the first saturated arc
+				k := 0
+				for n.cap[path[k]] > 0 {
+					k++
+				}
+				u = n.to[path[k]^1]
+				path = path[:k]
+				continue
+			}
+			// advance along an arc of the level graph
+			for ; it[u] < len(n.adj[u]); it[u]++ {
+				a := n.adj[u][it[u]]
+				if n.cap[a] > 0 && level[n.to[a]] == level[u]+1 {
+					break
+				}
+			}
+			if it[u] < len(n.adj[u]) {
+				a := n.adj[u][it[u]]
+				path = append(path, a)
+				u = n.to[a]
+				continue
+			}
+			// dead end
+			if u == s {
+				break
+			}
+			level[u] = -1
+			last := len(path) - 1
+			u = n.to[path[last]^1]
+			path = path[:last]
+			it[u]++
+		}
+	}
+	return
+}
+
+// levels computes the BFS level of each node from s in the residual
+// network.  It returns true if t is reachable.
+func (n *network) levels(s, t int, level []int) bool {
+	for i := range level {
+		level[i] = -1
+	}
+	level[s] = 0
+	q := []int{s}
+	for i := 0; i < len(q); i++ {
+		u := q[i]
+		for _, a := range n.adj[u] {
+			if v := n.to[a]; n.cap[a] > 0 && level[v] < 0 {
+				level[v] = level[u] + 1
+				q = append(q, v)
+			}
+		}
+	}
+	return level[t] >= 0
+}
+
+// pushRelabel computes maximum flow from s to t, leaving the flow as
+// residual capacities.
+func (n *network) pushRelabel(s, t int) float64 {
+	nn := len(n.nodes)
+	height := make([]int, nn)
+	excess := make([]float64, nn)
+	it := make([]int, nn) // current arc of each node
+	var q []int           // FIFO queue of active nodes
+	push := func(a int, f float64) {
+		v := n.to[a]
+		n.cap[a] -= f
+		n.cap[a^1] += f
+		excess[n.to[a^1]] -= f
+		if excess[v] == 0 && v != s && v != t {
+			q = append(q, v)
+		}
+		excess[v] += f
+	}
+	height[s] = nn
+	for _, a := range n.adj[s] {
+		if c := n.cap[a]; c > 0 {
+			push(a, c)
+		}
+	}
+	for len(q) > 0 {
+		u := q[0]
+		q = q[1:]
+		// discharge u
+		for excess[u] > 0 {
+			if it[u] == len(n.adj[u]) {
+				// relabel
+				h := math.MaxInt32
+				for _, a := range n.adj[u] {
+					if n.cap[a] > 0 && height[n.to[a]] < h {
+						h = height[n.to[a]]
+					}
+				}
+				height[u] = h + 1
+				it[u] = 0
+				continue
+			}
+			a := n.adj[u][it[u]]
+			if n.cap[a] > 0 && height[u] == height[n.to[a]]+1 {
+				push(a, math.Min(excess[u], n.cap[a]))
+			} else {
+				it[u]++
+			}
+		}
+	}
+	return excess[t]
+}
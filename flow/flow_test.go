@@ -0,0 +1,159 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package flow_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/adj"
+	"github.com/soniakeys/graph2/flow"
+)
+
+// capArc implements graph2.Capacity.  Arcs are used as map keys in the
+// flow result so a pointer type gives each arc a distinct identity.
+type capArc struct {
+	name string
+	c    float64
+}
+
+func (a *capArc) Capacity() float64 { return a.c }
+func (a *capArc) String() string    { return a.name }
+
+// network returns the example network of Cormen et al, Introduction to
+// Algorithms, figure 26.1.
+func network() (g adj.Digraph, arcs []*capArc) {
+	g = adj.Digraph{}
+	link := func(n1, n2 string, c float64) {
+		a := &capArc{n1 + "-" + n2, c}
+		arcs = append(arcs, a)
+		g.Link(n1, n2, a)
+	}
+	link("s", "v1", 16)
+	link("s", "v2", 13)
+	link("v1", "v3", 12)
+	link("v2", "v1", 4)
+	link("v2", "v4", 14)
+	link("v3", "v2", 9)
+	link("v3", "t", 20)
+	link("v4", "v3", 7)
+	link("v4", "t", 4)
+	return
+}
+
+// sortedCut formats a cut in a repeatable order.
+func sortedCut(cut []graph2.HalfNode) []string {
+	s := make([]string, len(cut))
+	for i, nd := range cut {
+		s[i] = fmt.Sprint(nd)
+	}
+	sort.Strings(s)
+	return s
+}
+
+func ExampleDinic() {
+	g, arcs := network()
+	value, arcFlow, cut := flow.Dinic(g["s"], g["t"])
+	fmt.Println("Max flow:", value)
+	for _, a := range arcs {
+		fmt.Printf("%s %g/%g\n", a, arcFlow[a], a.c)
+	}
+	fmt.Println("Min cut source side:", sortedCut(cut))
+	// Output:
+	// Max flow: 23
+	// s-v1 12/16
+	// s-v2 11/13
+	// v1-v3 12/12
+	// v2-v1 0/4
+	// v2-v4 11/14
+	// v3-v2 0/9
+	// v3-t 19/20
+	// v4-v3 7/7
+	// v4-t 4/4
+	// Min cut source side: [s v1 v2 v4]
+}
+
+func ExamplePushRelabel() {
+	g, _ := network()
+	value, _, cut := flow.PushRelabel(g["s"], g["t"])
+	fmt.Println("Max flow:", value)
+	fmt.Println("Min cut source side:", sortedCut(cut))
+	// Output:
+	// Max flow: 23
+	// Min cut source side: [s v1 v2 v4]
+}
+
+type maxFlow func(s, t graph2.HalfNode) (float64, map[graph2.Arc]float64, []graph2.HalfNode)
+
+func TestRandom(t *testing.T) {
+	for _, seed := range []int64{1, 2, 3, 4, 5} {
+		r := rand.New(rand.NewSource(seed))
+		g := adj.Digraph{}
+		for i := 0; i < 600; i++ {
+			g.Link(r.Intn(100), r.Intn(100),
+				&capArc{c: float64(r.Intn(20))})
+		}
+		g.Link(0, 0, &capArc{c: 1})
+		g.Link(99, 99, &capArc{c: 1})
+		var values []float64
+		for _, f := range []maxFlow{flow.Dinic, flow.PushRelabel} {
+			v, arcFlow, cut := f(g[0], g[99])
+			checkFlow(t, g, v, arcFlow, cut)
+			values = append(values, v)
+		}
+		if values[0] != values[1] {
+			t.Fatal("seed", seed, "flow values differ:", values)
+		}
+	}
+}
+
+// checkFlow verifies capacity constraints, conservation, and that the cut
+// capacity equals the flow value.
+func checkFlow(t *testing.T, g adj.Digraph, v float64, arcFlow map[graph2.Arc]float64, cut []graph2.HalfNode) {
+	inCut := map[graph2.HalfNode]bool{}
+	for _, nd := range cut {
+		inCut[nd] = true
+	}
+	if !inCut[g[0]] || inCut[g[99]] {
+		t.Fatal("cut does not separate source and sink")
+	}
+	net := map[*adj.Node]float64{}
+	cutCap := 0.
+	for _, nd := range g {
+		for _, h := range nd.Nbs {
+			a := h.Ed.(*capArc)
+			f := arcFlow[a]
+			if f < 0 || f > a.c+1e-9 {
+				t.Fatal("arc flow", f, "capacity", a.c)
+			}
+			net[nd] -= f
+			net[h.To.(*adj.Node)] += f
+			if inCut[nd] && !inCut[h.To] {
+				cutCap += a.c
+			}
+		}
+	}
+	for nd, f := range net {
+		switch nd {
+		case g[0]:
+			f = -f
+			fallthrough
+		case g[99]:
+			if math.Abs(f-v) > 1e-9 {
+				t.Fatal("net flow", f, "at", nd, "flow value", v)
+			}
+		default:
+			if math.Abs(f) > 1e-9 {
+				t.Fatal("flow not conserved at", nd, f)
+			}
+		}
+	}
+	if math.Abs(cutCap-v) > 1e-9 {
+		t.Fatal("cut capacity", cutCap, "flow value", v)
+	}
+}
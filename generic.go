@@ -0,0 +1,68 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph2
+
+// The types here parallel the interface{} based types of graph.go but take
+// type parameters.  Type parameter N is a node type, E is an arc or edge
+// type.  Functions operating on graphs through these types are checked at
+// compile time rather than asserting node and edge types at run time.
+//
+// Method names carry an "Of" suffix so that a single concrete type can
+// implement both the interface{} based and the type parameterized
+// interfaces.
+//
+// Weighted and Estimator need no parallel type.  Weighted for example
+// serves directly as a constraint on an edge type parameter E.
+
+// HalfOf is a type parameterized Half.  It associates an arc or edge with
+// a single node at the end of the arc or edge.
+type HalfOf[N, E any] struct {
+	Ed E // arc or edge
+	To N
+}
+
+// FromHalfOf is a type parameterized FromHalf.  It associates an arc or edge
+// with a single node at the end of the arc or edge.
+type FromHalfOf[N, E any] struct {
+	From N
+	Ed   E // arc or edge
+}
+
+// AdjHalfVisitorOf is the argument type for HalfNodeOf.VisitAdjHalfsOf.
+type AdjHalfVisitorOf[N, E any] func(HalfOf[N, E])
+
+// HalfNodeOf is a type parameterized HalfNode.  It is a constraint,
+// typically satisfied by a type N with a method taking visitor functions
+// over HalfOf[N, E].
+//
+// As nodes are used as map keys, N must be comparable.
+type HalfNodeOf[N, E any] interface {
+	comparable
+	// VisitAdjHalfsOf should call the visitor function for each adjacent
+	// half arc or half edge.
+	VisitAdjHalfsOf(AdjHalfVisitorOf[N, E])
+}
+
+// EstimateNodeOf is a type parameterized EstimateNode.  It describes a node
+// that can provide a distance estimate to another node of the same type.
+type EstimateNodeOf[N, E any] interface {
+	HalfNodeOf[N, E]
+	EstimateOf(N) float64
+}
+
+// AdjNodeVisitorOf is the argument type for NodeOf.VisitAdjNodesOf.
+type AdjNodeVisitorOf[N any] func(n N) (ok bool)
+
+// NodeOf is a type parameterized Node.  It is a constraint, typically
+// satisfied by a type N with a method taking visitor functions over N.
+//
+// VisitAdjNodesOf must iterate over adjacent nodes as documented for
+// Node.VisitAdjNodes.
+type NodeOf[N any] interface {
+	comparable
+	VisitAdjNodesOf(AdjNodeVisitorOf[N]) (ok bool)
+}
+
+// LevelVisitorOf is a type parameterized LevelVisitor.
+type LevelVisitorOf[N any] func(n N, level int) (ok bool)
@@ -23,6 +23,21 @@ type Node interface {
 // Node.
 type AdjNodeVisitor func(n Node) (ok bool)
 
+// An IndexedNode has a dense integer index within its graph.
+//
+// IndexedNode is optional.  Search functions detect it on node arguments
+// and keep per node state in slices and bitsets rather than maps.  All nodes
+// of a graph must then implement IndexedNode with the same NumNodes, and
+// NodeID must be distinct for distinct nodes and in the range 0 through
+// NumNodes()-1.  Slices are allocated with NumNodes elements for each
+// search, so the index pays off for searches that reach a good fraction of
+// the graph.  It pays off most for node types that are pointers, as values
+// of larger types are copied to the heap to call NodeID.
+type IndexedNode interface {
+	NodeID() int   // index of the node
+	NumNodes() int // number of nodes in the graph
+}
+
 // A LevelVisitor is an argument to some search or traversal functions.
 // The search or traversal function will call LevelVisitor for each node of a
 // graph as the function traverses the graph.  Argument n is the node being
@@ -110,6 +125,27 @@ type HalfNode interface {
 // AdjHalfVisitor is the argument type for HalfNode.VisitAdjHalfs.
 type AdjHalfVisitor func(Half)
 
+// An InHalfNode represents a reverse adjacency relationship.
+//
+// The relationship is by half arcs or half edges that lead to the InHalfNode
+// directly from other HalfNodes.
+type InHalfNode interface {
+	// VisitInHalfs should call the InHalfVisitor function for each half
+	// arc or half edge leading to the node.  In an undirected graph these
+	// are the same edges visited by VisitAdjHalfs.
+	VisitInHalfs(InHalfVisitor)
+}
+
+// InHalfVisitor is the argument type for InHalfNode.VisitInHalfs.
+type InHalfVisitor func(FromHalf)
+
+// A BiHalfNode can be traversed both with and against the direction of
+// arcs.  The Half and FromHalf values visited must hold BiHalfNodes.
+type BiHalfNode interface {
+	HalfNode
+	InHalfNode
+}
+
 // Half is a half arc or half edge.  It associates an arc or edge with
 // a single node at the end of the arc or edge.  In a directed graph, Ed
 // represents an arc and To is a node that the arc leads to.
@@ -144,6 +180,13 @@ type Weighted interface {
 	Weight() float64
 }
 
+// Capacity is an object such as an arc that describes a capacity, the
+// maximum quantity that can flow along the arc.  It is typically
+// non-negative.
+type Capacity interface {
+	Capacity() float64
+}
+
 // An Estimator provides a distance estimate from itself to an EstimateNode.
 // This estimate is often called h, or a heuristic estimate.
 type Estimator interface {
@@ -0,0 +1,19 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+// Package internal holds helpers shared by packages of graph2.
+package internal
+
+// Equal compares arc, edge, or node values as interface values.  Unlike
+// ==, it does not panic when the values are of the same dynamic type and
+// that type is not comparable, or holds values that are not comparable,
+// such as a struct with an interface field holding a slice.  It returns
+// false instead.
+func Equal(a, b interface{}) (eq bool) {
+	defer func() {
+		if recover() != nil {
+			eq = false
+		}
+	}()
+	return a == b
+}
@@ -0,0 +1,34 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/soniakeys/graph2/internal"
+)
+
+func TestEqual(t *testing.T) {
+	type holder struct{ v interface{} }
+	s := []int{1}
+	for _, c := range []struct {
+		a, b interface{}
+		want bool
+	}{
+		{nil, nil, true},
+		{nil, 1, false},
+		{1, 1, true},
+		{1, 1., false},
+		{"a", "a", true},
+		{s, s, false},                               // not comparable
+		{holder{1}, holder{1}, true},                // comparable struct
+		{holder{s}, holder{s}, false},               // struct holding a slice
+		{holder{s}, holder{1}, false},               // one holding a slice
+		{holder{map[int]int{}}, holder{nil}, false}, // one holding a map
+	} {
+		if got := internal.Equal(c.a, c.b); got != c.want {
+			t.Errorf("Equal(%v, %v) = %t, want %t", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ package search
 
 import (
 	"container/heap"
+	"context"
 	"math"
 
 	"github.com/soniakeys/graph2"
@@ -45,40 +46,117 @@ import (
 // from the start node, the returned Half list will be nil and the path
 // length +Inf.
 func AStarA(start, end graph2.EstimateNode) ([]graph2.Half, float64) {
+	path, dist := AStarAOf(estimateNode{start}, estimateNode{end})
+	return estimatePath(path), dist
+}
+
+// AStarAOf is a type parameterized AStarA.
+//
+// Node type N and edge type E are checked at compile time.  The edge member
+// of the first element of the returned path will be the zero value of E.
+// Otherwise the result is as documented for AStarA.
+func AStarAOf[N graph2.EstimateNodeOf[N, E], E graph2.Weighted](start, end N) ([]graph2.HalfOf[N, E], float64) {
+	path, dist, _ := astarA(start, end, nil)
+	return path, dist
+}
+
+// astarOpt holds optional parameters for astarA and astarM.
+type astarOpt[N comparable, E any] struct {
+	// ctx, if not nil, is checked every ctxCheck nodes expanded.
+	ctx context.Context
+	// lim bounds the search.
+	lim Limits
+	// tree, if not nil, receives each node expanded, along with the arc
+	// leading to it.
+	tree map[N]graph2.FromHalfOf[N, E]
+	// settled counts nodes expanded within limits.
+	settled int
+}
+
+// settle counts and records node p as popped from the open heap.  Nodes
+// beyond o.lim.MaxHops are neither counted nor recorded, but they are still
+// expanded so that paths found remain shortest paths.  settle returns true
+// if the search should stop with no path, either because o.lim.MaxSettled
+// has been reached or because p is node end and is beyond MaxHops.
+func (o *astarOpt[N, E]) settle(p *rNode[N, E], end N) (stop bool) {
+	if o == nil {
+		return false
+	}
+	if o.lim.MaxSettled > 0 && o.settled >= o.lim.MaxSettled {
+		return true
+	}
+	if o.lim.MaxHops > 0 && p.n-1 > o.lim.MaxHops {
+		return p.nd == end
+	}
+	o.settled++
+	if o.tree != nil {
+		var from graph2.FromHalfOf[N, E]
+		if p.prevNode != nil {
+			from = graph2.FromHalfOf[N, E]{p.prevNode.nd, p.prevEdge}
+		}
+		o.tree[p.nd] = from
+	}
+	return false
+}
+
+// beyond returns true if path distance g is beyond o.lim.MaxDist.
+func (o *astarOpt[N, E]) beyond(g float64) bool {
+	return o != nil && o.lim.MaxDist > 0 && g > o.lim.MaxDist
+}
+
+// done checks the context of o, if any, every ctxCheck expansions.
+func (o *astarOpt[N, E]) done(expanded int) error {
+	if o == nil || o.ctx == nil || expanded%ctxCheck != 0 {
+		return nil
+	}
+	return o.ctx.Err()
+}
+
+// astarA implements AStarA.  Argument o may be nil.
+//
+// If a context of o is done before the search completes, astarA returns
+// the context error along with the best path found so far to end, if end
+// has been reached.
+func astarA[N graph2.EstimateNodeOf[N, E], E graph2.Weighted](start, end N, o *astarOpt[N, E]) ([]graph2.HalfOf[N, E], float64, error) {
 	// start node is reached initially
-	p := &rNode{
+	p := &rNode[N, E]{
 		nd: start,
-		f:  start.Estimate(end),
+		f:  start.EstimateOf(end),
 		n:  1, // path length is 1 node
 	}
 	// r is a list of all nodes reached so far.
 	// the chain of nodes following the prev member represents the
 	// best path found so far from the start to this node.
-	r := map[graph2.EstimateNode]*rNode{start: p}
+	r := newNodeMap[N, *rNode[N, E]](start)
+	r.set(start, p)
 	// oh is a heap of nodes "open" for exploration.  nodes go on the heap
 	// when they get an initial or new "g" path distance, and therefore a
 	// new "f" which serves as priority for exploration.
-	oh := openHeap{p}
-	for len(oh) > 0 {
-		bestPath := heap.Pop(&oh).(*rNode)
+	oh := openHeap[N, E]{p}
+	for expanded := 0; len(oh) > 0; expanded++ {
+		if err := o.done(expanded); err != nil {
+			if p, ok := r.get(end); ok {
+				return p.path(), p.g, err
+			}
+			return nil, math.Inf(1), err
+		}
+		bestPath := heap.Pop(&oh).(*rNode[N, E])
 		bestNode := bestPath.nd
+		if o.settle(bestPath, end) {
+			return nil, math.Inf(1), nil // limit reached
+		}
 		if bestNode == end {
 			// done
-			dist := bestPath.g
-			i := bestPath.n
-			path := make([]graph2.Half, i)
-			for i > 0 {
-				i--
-				path[i] = graph2.Half{bestPath.prevEdge, bestPath.nd}
-				bestPath = bestPath.prevNode
-			}
-			return path, dist
+			return bestPath.path(), bestPath.g, nil
 		}
-		bestNode.VisitAdjHalfs(func(nb graph2.Half) {
-			ed := nb.Ed.(graph2.Weighted)
-			nd := nb.To.(graph2.EstimateNode)
+		bestNode.VisitAdjHalfsOf(func(nb graph2.HalfOf[N, E]) {
+			ed := nb.Ed
+			nd := nb.To
 			g := bestPath.g + ed.Weight()
-			if alt, reached := r[nd]; reached {
+			if o.beyond(g) {
+				return
+			}
+			if alt, reached := r.get(nd); reached {
 				if g > alt.g {
 					// new path to nd is longer than some alternate path
 					return
@@ -93,7 +171,7 @@ func AStarA(start, end graph2.EstimateNode) ([]graph2.Half, float64) {
 				alt.prevNode = bestPath
 				alt.prevEdge = ed
 				alt.g = g
-				alt.f = g + nd.Estimate(end)
+				alt.f = g + nd.EstimateOf(end)
 				alt.n = bestPath.n + 1
 				if alt.rx < 0 {
 					heap.Push(&oh, alt)
@@ -102,20 +180,20 @@ func AStarA(start, end graph2.EstimateNode) ([]graph2.Half, float64) {
 				}
 			} else {
 				// bestNode being reached for the first time.
-				p := &rNode{
+				p := &rNode[N, E]{
 					nd:       nd,
 					prevNode: bestPath,
 					prevEdge: ed,
 					g:        g,
-					f:        g + nd.Estimate(end),
+					f:        g + nd.EstimateOf(end),
 					n:        bestPath.n + 1,
 				}
-				r[nd] = p         // add to list of reached nodes
+				r.set(nd, p)      // add to list of reached nodes
 				heap.Push(&oh, p) // and it's now open for exploration
 			}
 		})
 	}
-	return nil, math.Inf(1) // no path
+	return nil, math.Inf(1), nil // no path
 }
 
 // AStarM is A* optimized for monotonic estimates.
@@ -124,9 +202,26 @@ func AStarA(start, end graph2.EstimateNode) ([]graph2.Half, float64) {
 // node B is adjacent to node A with edge AB, then
 // A.Estimate(C) <= AB.Weight() + B.Estimate(C).
 func AStarM(start, end graph2.EstimateNode) ([]graph2.Half, float64) {
-	p := &rNode{
+	path, dist := AStarMOf(estimateNode{start}, estimateNode{end})
+	return estimatePath(path), dist
+}
+
+// AStarMOf is a type parameterized AStarM.
+//
+// Node type N and edge type E are checked at compile time.  The edge member
+// of the first element of the returned path will be the zero value of E.
+// Otherwise the result is as documented for AStarM.
+func AStarMOf[N graph2.EstimateNodeOf[N, E], E graph2.Weighted](start, end N) ([]graph2.HalfOf[N, E], float64) {
+	path, dist, _ := astarM(start, end, nil)
+	return path, dist
+}
+
+// astarM implements AStarM.  Argument o may be nil.  Results are as for
+// astarA.
+func astarM[N graph2.EstimateNodeOf[N, E], E graph2.Weighted](start, end N, o *astarOpt[N, E]) ([]graph2.HalfOf[N, E], float64, error) {
+	p := &rNode[N, E]{
 		nd: start,
-		f:  start.Estimate(end),
+		f:  start.EstimateOf(end),
 		n:  1,
 	}
 
@@ -135,43 +230,48 @@ func AStarM(start, end graph2.EstimateNode) ([]graph2.Half, float64) {
 	// lists, open and closed. open contains nodes "open" for exploration.
 	// nodes are added to the list as they are reached, then moved to
 	// closed as they are found to be on the best path.
-	open := map[graph2.EstimateNode]*rNode{start: p}
-	closed := map[graph2.EstimateNode]struct{}{}
+	open := newNodeMap[N, *rNode[N, E]](start)
+	open.set(start, p)
+	closed := newNodeMap[N, struct{}](start)
 
-	oh := openHeap{p}
-	for len(oh) > 0 {
-		bestPath := heap.Pop(&oh).(*rNode)
+	oh := openHeap[N, E]{p}
+	for expanded := 0; len(oh) > 0; expanded++ {
+		if err := o.done(expanded); err != nil {
+			if p, ok := open.get(end); ok {
+				return p.path(), p.g, err
+			}
+			return nil, math.Inf(1), err
+		}
+		bestPath := heap.Pop(&oh).(*rNode[N, E])
 		bestNode := bestPath.nd
+		if o.settle(bestPath, end) {
+			return nil, math.Inf(1), nil // limit reached
+		}
 		if bestNode == end {
 			// done
-			dist := bestPath.g
-			i := bestPath.n
-			path := make([]graph2.Half, i)
-			for bestPath != nil {
-				i--
-				path[i] = graph2.Half{bestPath.prevEdge, bestPath.nd}
-				bestPath = bestPath.prevNode
-			}
-			return path, dist
+			return bestPath.path(), bestPath.g, nil
 		}
 
 		// difference from AStarA:
 		// move nodes to closed list as they are found to be best so far.
-		delete(open, bestNode)
-		closed[bestNode] = struct{}{}
+		open.del(bestNode)
+		closed.set(bestNode, struct{}{})
 
-		bestNode.VisitAdjHalfs(func(nb graph2.Half) {
-			ed := nb.Ed.(graph2.Weighted)
-			nd := nb.To.(graph2.EstimateNode)
+		bestNode.VisitAdjHalfsOf(func(nb graph2.HalfOf[N, E]) {
+			ed := nb.Ed
+			nd := nb.To
 
 			// difference from AStarA:
 			// Monotonicity means that f cannot be improved.
-			if _, ok := closed[nd]; ok {
+			if _, ok := closed.get(nd); ok {
 				return
 			}
 
 			g := bestPath.g + ed.Weight()
-			if alt, reached := open[nd]; reached {
+			if o.beyond(g) {
+				return
+			}
+			if alt, reached := open.get(nd); reached {
 				if g > alt.g {
 					// new path to nd is longer than some alternate path
 					return
@@ -186,7 +286,7 @@ func AStarM(start, end graph2.EstimateNode) ([]graph2.Half, float64) {
 				alt.prevNode = bestPath
 				alt.prevEdge = ed
 				alt.g = g
-				alt.f = g + nd.Estimate(end)
+				alt.f = g + nd.EstimateOf(end)
 				alt.n = bestPath.n + 1
 
 				// difference from AStarA:
@@ -195,55 +295,95 @@ func AStarM(start, end graph2.EstimateNode) ([]graph2.Half, float64) {
 				heap.Fix(&oh, alt.rx)
 			} else {
 				// bestNode being reached for the first time.
-				p := &rNode{
+				p := &rNode[N, E]{
 					nd:       nd,
 					prevNode: bestPath,
 					prevEdge: ed,
 					g:        g,
-					f:        g + nd.Estimate(end),
+					f:        g + nd.EstimateOf(end),
 					n:        bestPath.n + 1,
 				}
-				open[nd] = p      // new node is now open for exploration.
+				open.set(nd, p)   // new node is now open for exploration.
 				heap.Push(&oh, p) // keep heap matching open list.
 			}
 		})
 	}
-	return nil, math.Inf(1) // no path
+	return nil, math.Inf(1), nil // no path
 }
 
 // rNode holds data for a "reached" node
-type rNode struct {
-	nd       graph2.EstimateNode
-	prevNode *rNode         // chain encodes path back to start
-	prevEdge graph2.Weighted // edge from prevNode to the node of this struct
-	g        float64        // "g" best known path distance from start node
-	f        float64        // "g+h", path dist + heuristic estimate
-	n        int            // number of nodes in path
-	rx       int            // heap.Remove index
+type rNode[N, E any] struct {
+	nd       N
+	prevNode *rNode[N, E] // chain encodes path back to start
+	prevEdge E            // edge from prevNode to the node of this struct
+	g        float64      // "g" best known path distance from start node
+	f        float64      // "g+h", path dist + heuristic estimate
+	n        int          // number of nodes in path
+	rx       int          // heap.Remove index
 }
 
-type openHeap []*rNode
+// path recovers the path to p by following the prevNode chain.
+func (p *rNode[N, E]) path() []graph2.HalfOf[N, E] {
+	i := p.n
+	path := make([]graph2.HalfOf[N, E], i)
+	for i > 0 {
+		i--
+		path[i] = graph2.HalfOf[N, E]{p.prevEdge, p.nd}
+		p = p.prevNode
+	}
+	return path
+}
+
+type openHeap[N, E any] []*rNode[N, E]
 
 // implement container/heap
-func (h openHeap) Len() int           { return len(h) }
-func (h openHeap) Less(i, j int) bool { return h[i].f < h[j].f }
-func (h openHeap) Swap(i, j int) {
+func (h openHeap[N, E]) Len() int           { return len(h) }
+func (h openHeap[N, E]) Less(i, j int) bool { return h[i].f < h[j].f }
+func (h openHeap[N, E]) Swap(i, j int) {
 	h[i], h[j] = h[j], h[i]
 	h[i].rx = i
 	h[j].rx = j
 }
-func (p *openHeap) Push(x interface{}) {
+func (p *openHeap[N, E]) Push(x interface{}) {
 	h := *p
 	rx := len(h)
-	h = append(h, x.(*rNode))
+	h = append(h, x.(*rNode[N, E]))
 	h[rx].rx = rx
 	*p = h
 }
 
-func (p *openHeap) Pop() interface{} {
+func (p *openHeap[N, E]) Pop() interface{} {
 	h := *p
 	last := len(h) - 1
 	*p = h[:last]
 	h[last].rx = -1
 	return h[last]
 }
+
+// estimateNode adapts a graph2.EstimateNode with graph2.Weighted edges to
+// graph2.EstimateNodeOf.  It allows the interface{} based API to share code
+// with the type parameterized API.
+type estimateNode struct{ graph2.EstimateNode }
+
+func (n estimateNode) VisitAdjHalfsOf(v graph2.AdjHalfVisitorOf[estimateNode, graph2.Weighted]) {
+	n.VisitAdjHalfs(func(h graph2.Half) {
+		v(graph2.HalfOf[estimateNode, graph2.Weighted]{
+			h.Ed.(graph2.Weighted), estimateNode{h.To.(graph2.EstimateNode)}})
+	})
+}
+
+func (n estimateNode) EstimateOf(end estimateNode) float64 {
+	return n.Estimate(end.EstimateNode)
+}
+
+// estimatePath converts a path of adapted nodes back to a graph2.Half path.
+func estimatePath(p []graph2.HalfOf[estimateNode, graph2.Weighted]) []graph2.Half {
+	if p == nil {
+		return nil
+	}
+	path := make([]graph2.Half, len(p))
+	for i, h := range p {
+		path[i] = graph2.Half{h.Ed, h.To.EstimateNode}
+	}
+	return path
+}
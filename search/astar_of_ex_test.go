@@ -0,0 +1,77 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// AStarAOf and AStarMOf require a node type that satisfies
+// graph2.EstimateNodeOf and an edge type that implements graph2.Weighted.
+
+type (
+	estOfNode struct {
+		name string  // node name
+		h    float64 // heuristic distance estimate to end node
+		nbs  []graph2.HalfOf[*estOfNode, estArc]
+	}
+)
+
+// Two methods satisfy graph2.EstimateNodeOf[*estOfNode, estArc].
+func (n *estOfNode) VisitAdjHalfsOf(v graph2.AdjHalfVisitorOf[*estOfNode, estArc]) {
+	for _, a := range n.nbs {
+		v(a)
+	}
+}
+func (n *estOfNode) EstimateOf(*estOfNode) float64 { return n.h }
+
+// Implement fmt.Stringer to make output easy.
+func (n *estOfNode) String() string { return n.name }
+
+// One more method to make graph construction easy.
+func (n *estOfNode) link(n2 *estOfNode, weight int) {
+	n.nbs = append(n.nbs, graph2.HalfOf[*estOfNode, estArc]{estArc(weight), n2})
+}
+
+func estOfGraph() (a, e *estOfNode) {
+	a = &estOfNode{name: "a", h: 19}
+	b := &estOfNode{name: "b", h: 20}
+	c := &estOfNode{name: "c", h: 10}
+	d := &estOfNode{name: "d", h: 6}
+	e = &estOfNode{name: "e", h: 0}
+	f := &estOfNode{name: "f", h: 9}
+	a.link(b, 7)
+	a.link(c, 9)
+	a.link(f, 14)
+	b.link(c, 10)
+	b.link(d, 15)
+	c.link(d, 11)
+	c.link(f, 2)
+	d.link(e, 6)
+	e.link(f, 9)
+	return
+}
+
+func ExampleAStarAOf() {
+	a, e := estOfGraph()
+	p, l := search.AStarAOf(a, e)
+	fmt.Println("Shortest path:", p)
+	fmt.Println("Path length:", l)
+	// Output:
+	// Shortest path: [{0 a} {9 c} {11 d} {6 e}]
+	// Path length: 26
+}
+
+func ExampleAStarMOf() {
+	a, e := estOfGraph()
+	p, l := search.AStarMOf(a, e)
+	fmt.Println("Shortest path:", p)
+	fmt.Println("Path length:", l)
+	// Output:
+	// Shortest path: [{0 a} {9 c} {11 d} {6 e}]
+	// Path length: 26
+}
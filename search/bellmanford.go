@@ -0,0 +1,117 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import "github.com/soniakeys/graph2"
+
+// BellmanFord finds the shortest paths from the start node to all other
+// nodes in a graph, where path length is the sum of edge weights and
+// weights may be negative.
+//
+// Adjacency relationships between nodes can represent a general directed or
+// undirected graph2.  Note though that in an undirected graph any edge with
+// a negative weight is itself a negative cycle.
+//
+// Edges connecting nodes must implement graph2.Weighted.  Weights may be
+// negative but must not be an Inf or NaN.
+//
+// The implementation is the queue-based variant of Bellman-Ford sometimes
+// called SPFA, the "shortest path faster algorithm."  Nodes are relaxed
+// only when the distance to some adjacent node has improved.
+//
+// If no negative cycle is reachable from the start node, tree is the same
+// as the result of DijkstraAllPaths and negCycle is nil.  The tree map has
+// a key for each node reachable from the start node.  The element value of
+// each key is a half edge representing the previous node along the shortest
+// path.  The start node is included in the result, with a zero value element.
+//
+// If a negative cycle is reachable from the start node, tree is nil and
+// negCycle holds the arcs and nodes of one such cycle.  The arc of each
+// element leads from the node of the previous element; the arc of the first
+// element leads from the node of the last element.
+func BellmanFord(start graph2.HalfNode) (tree map[graph2.HalfNode]graph2.FromHalf, negCycle []graph2.Half) {
+	if start == nil {
+		return nil, nil
+	}
+	r := map[graph2.HalfNode]*spfaNode{start: {n: 1, inQ: true}}
+	prev := map[graph2.HalfNode]graph2.FromHalf{start: graph2.FromHalf{}}
+	q := []graph2.HalfNode{start}
+	for len(q) > 0 {
+		current := q[0]
+		q = q[1:]
+		cr := r[current]
+		cr.inQ = false
+		current.VisitAdjHalfs(func(a graph2.Half) {
+			if negCycle != nil {
+				return
+			}
+			dist := cr.dist + a.Ed.(graph2.Weighted).Weight()
+			nr, ok := r[a.To]
+			if !ok {
+				nr = &spfaNode{}
+				r[a.To] = nr
+			} else if dist >= nr.dist {
+				return // it's no help
+			}
+			nr.dist = dist
+			nr.n = cr.n + 1
+			prev[a.To] = graph2.FromHalf{current, a.Ed}
+			// Without negative cycles, paths found are simple and so cannot
+			// have more nodes than have been reached.  A longer path means
+			// a negative cycle is reachable, although it may take more
+			// relaxations before the cycle shows up in the prev links.
+			if nr.n > len(r) {
+				if negCycle = prevCycle(prev, a.To); negCycle != nil {
+					return
+				}
+			}
+			if !nr.inQ {
+				nr.inQ = true
+				q = append(q, a.To)
+			}
+		})
+		if negCycle != nil {
+			return nil, negCycle
+		}
+	}
+	return prev, nil
+}
+
+// spfaNode holds data per node reached by BellmanFord.
+type spfaNode struct {
+	dist float64 // best path distance found so far
+	n    int     // number of nodes in path
+	inQ  bool    // node is in the queue
+}
+
+// prevCycle follows prev links from nd looking for a cycle.  It returns
+// the cycle if one is found, or nil if the links lead back to the start
+// node.
+func prevCycle(prev map[graph2.HalfNode]graph2.FromHalf, nd graph2.HalfNode) []graph2.Half {
+	seen := map[graph2.HalfNode]struct{}{}
+	for {
+		if _, ok := seen[nd]; ok {
+			break // nd is on a cycle
+		}
+		seen[nd] = struct{}{}
+		from := prev[nd]
+		if from.From == nil {
+			return nil // reached start
+		}
+		nd = from.From
+	}
+	// collect the cycle, tracing it backward from nd, then reverse it.
+	var c []graph2.Half
+	for current := nd; ; {
+		from := prev[current]
+		c = append(c, graph2.Half{from.Ed, current})
+		if current = from.From; current == nd {
+			break
+		}
+	}
+	for i, j := 0, len(c)-1; i < j; i, j = i+1, j-1 {
+		c[i], c[j] = c[j], c[i]
+	}
+	return c
+}
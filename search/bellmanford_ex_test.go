@@ -0,0 +1,78 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/soniakeys/graph2/search"
+)
+
+// BellmanFord uses the same node and arc types as DijkstraAllPaths.
+// Arc weights can be negative though.
+
+func ExampleBellmanFord() {
+	a := &dapNode{name: "a"}
+	b := &dapNode{name: "b"}
+	c := &dapNode{name: "c"}
+	d := &dapNode{name: "d"}
+	e := &dapNode{name: "e"}
+	a.link(b, 4)
+	a.link(c, 5)
+	b.link(d, 6)
+	c.link(b, -3)
+	c.link(e, 8)
+	d.link(e, -2)
+	from, negCycle := search.BellmanFord(a)
+	fmt.Println("Negative cycle:", negCycle)
+	// format output by walking each node of the result back to start
+	as := make([]string, len(from))
+	i := 0
+	for nd, fh := range from {
+		s := fmt.Sprint(nd)
+		for fh.From != nil {
+			s = fmt.Sprintf("%s %g %s", fh.From, fh.Ed, s)
+			fh = from[fh.From]
+		}
+		as[i] = s
+		i++
+	}
+	// sort for test repeatability
+	sort.Strings(as)
+	for _, s := range as {
+		fmt.Println(s)
+	}
+	// Output:
+	// Negative cycle: []
+	// a
+	// a 5 c
+	// a 5 c -3 b
+	// a 5 c -3 b 6 d
+	// a 5 c -3 b 6 d -2 e
+}
+
+func ExampleBellmanFord_negativeCycle() {
+	a := &dapNode{name: "a"}
+	b := &dapNode{name: "b"}
+	c := &dapNode{name: "c"}
+	d := &dapNode{name: "d"}
+	a.link(b, 1)
+	b.link(c, 2)
+	c.link(d, 3)
+	d.link(b, -6)
+	from, negCycle := search.BellmanFord(a)
+	fmt.Println("Tree:", from)
+	fmt.Println("Cycle length:", len(negCycle))
+	// the cycle can be found starting at any of its nodes.  rotate it
+	// to start at b for test repeatability.
+	for negCycle[0].To != b {
+		negCycle = append(negCycle[1:], negCycle[0])
+	}
+	fmt.Println("Negative cycle:", negCycle)
+	// Output:
+	// Tree: map[]
+	// Cycle length: 3
+	// Negative cycle: [{-6 b} {2 c} {3 d}]
+}
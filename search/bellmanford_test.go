@@ -0,0 +1,115 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// pathDist sums weights along a tree path back to the start node.
+func pathDist(tree map[graph2.HalfNode]graph2.FromHalf, nd graph2.HalfNode) float64 {
+	d := 0.
+	for fh := tree[nd]; fh.From != nil; fh = tree[fh.From] {
+		d += fh.Ed.(graph2.Weighted).Weight()
+	}
+	return d
+}
+
+func TestBellmanFordDijkstra(t *testing.T) {
+	// with non-negative weights, BellmanFord must agree with Dijkstra.
+	start, _ := r(1000, 3000, 66)
+	dt := search.DijkstraAllPaths(start)
+	bt, negCycle := search.BellmanFord(start)
+	if negCycle != nil {
+		t.Fatal("unexpected negative cycle", negCycle)
+	}
+	if len(bt) != len(dt) {
+		t.Fatal("reached", len(bt), "nodes, Dijkstra reached", len(dt))
+	}
+	for nd := range dt {
+		if _, ok := bt[nd]; !ok {
+			t.Fatal("node not reached:", nd)
+		}
+		bd := pathDist(bt, nd)
+		dd := pathDist(dt, nd)
+		if math.Abs(bd-dd) > 1e-9 {
+			t.Fatal("node", nd, "distance", bd, "Dijkstra distance", dd)
+		}
+	}
+}
+
+// bfGraph returns nodes named a, b, ... with arcs given as from, to, weight.
+func bfGraph(n int, arcs [][3]int) []*dapNode {
+	nodes := make([]*dapNode, n)
+	for i := range nodes {
+		nodes[i] = &dapNode{name: string(rune('a' + i))}
+	}
+	for _, a := range arcs {
+		nodes[a[0]].link(nodes[a[1]], a[2])
+	}
+	return nodes
+}
+
+func TestBellmanFordNegativeArc(t *testing.T) {
+	// a->c->b with the negative arc is shorter than a->b.  no cycle.
+	nodes := bfGraph(4, [][3]int{
+		{0, 1, 4}, {0, 2, 5}, {2, 1, -3}, {1, 3, 1}, {3, 2, 2},
+	})
+	tree, negCycle := search.BellmanFord(nodes[0])
+	if negCycle != nil {
+		t.Fatal("unexpected negative cycle", negCycle)
+	}
+	for i, want := range []float64{0, 2, 5, 3} {
+		if d := pathDist(tree, nodes[i]); d != want {
+			t.Fatal("node", nodes[i], "distance", d, "want", want)
+		}
+	}
+	if from := tree[nodes[1]]; from.From != nodes[2] || from.Ed != dapArc(-3) {
+		t.Fatal("node b reached from", from)
+	}
+}
+
+func TestBellmanFordNegativeCycle(t *testing.T) {
+	// cycle b->c->d->b has weight -1.  e is reachable past the cycle.
+	// f->a->f is a negative cycle not reachable from a.
+	nodes := bfGraph(6, [][3]int{
+		{0, 1, 1}, {1, 2, -2}, {2, 3, 1}, {3, 1, 0}, {2, 4, 3},
+		{5, 0, -5}, {0, 5, 1}, {0, 5, 1},
+	})
+	tree, negCycle := search.BellmanFord(nodes[1])
+	if tree != nil {
+		t.Fatal("tree returned with negative cycle")
+	}
+	if len(negCycle) != 3 {
+		t.Fatal("cycle", negCycle)
+	}
+	w := 0.
+	onCycle := map[graph2.HalfNode]bool{}
+	for i, h := range negCycle {
+		from := negCycle[(i+len(negCycle)-1)%len(negCycle)].To.(*dapNode)
+		found := false
+		for _, a := range from.nbs {
+			if a == h {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatal("cycle arc", h, "does not lead from", from)
+		}
+		w += h.Ed.(graph2.Weighted).Weight()
+		onCycle[h.To] = true
+	}
+	if w != -1 || !onCycle[nodes[1]] || !onCycle[nodes[2]] || !onCycle[nodes[3]] {
+		t.Fatal("cycle", negCycle, "weight", w)
+	}
+
+	// from e, neither cycle is reachable.
+	if tree, negCycle = search.BellmanFord(nodes[4]); negCycle != nil || len(tree) != 1 {
+		t.Fatal("from e:", tree, negCycle)
+	}
+}
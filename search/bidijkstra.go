@@ -0,0 +1,167 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/soniakeys/graph2"
+)
+
+// DijkstraBidirectional finds a shortest path between two nodes.
+//
+// It runs two Dijkstra searches, one forward from the start node following
+// VisitAdjHalfs and one backward from the end node following VisitInHalfs.
+// The searches alternate, each time expanding the side with the smaller
+// tentative distance.  Whenever a node is reached by both searches, the
+// combined path length is a candidate for the shortest path.  The search
+// stops when the sum of the smallest tentative distances of the two sides
+// is no less than the best candidate.  On large graphs this typically
+// settles far fewer nodes than DijkstraShortestPath.
+//
+// Arguments start and end must implement graph2.BiHalfNode, as must all
+// nodes reached from them.  Edges connecting nodes must implement
+// graph2.Weighted.  Weights must be non-negative and must not be an Inf
+// or NaN.
+//
+// The result is as for DijkstraShortestPath.  The first element of the
+// returned path will be the start node, with a nil edge.  Remaining elements
+// give the found path of edges and nodes.  Also returned is the total path
+// length.  If the end node cannot be reached from the start node, the
+// returned Half list will be nil and the path length +Inf.
+func DijkstraBidirectional(start, end graph2.BiHalfNode) ([]graph2.Half, float64) {
+	if start == nil || end == nil {
+		return nil, math.Inf(1)
+	}
+	if start == end {
+		return []graph2.Half{{nil, start}}, 0
+	}
+	f := newBDSearch(start)
+	b := newBDSearch(end)
+	mu := math.Inf(1)          // length of best path found so far
+	var meet graph2.BiHalfNode // node where forward and backward paths meet
+	for len(f.h) > 0 && len(b.h) > 0 {
+		if f.h[0].dist+b.h[0].dist >= mu {
+			break // no shorter path is possible
+		}
+		if f.h[0].dist <= b.h[0].dist {
+			current := heap.Pop(&f.h).(*bdNode)
+			current.nd.VisitAdjHalfs(func(a graph2.Half) {
+				l := f.relax(current, a.To.(graph2.BiHalfNode), a.Ed)
+				if l == nil {
+					return
+				}
+				if bl, ok := b.r[l.nd]; ok && l.dist+bl.dist < mu {
+					mu = l.dist + bl.dist
+					meet = l.nd
+				}
+			})
+		} else {
+			current := heap.Pop(&b.h).(*bdNode)
+			current.nd.VisitInHalfs(func(a graph2.FromHalf) {
+				l := b.relax(current, a.From.(graph2.BiHalfNode), a.Ed)
+				if l == nil {
+					return
+				}
+				if fl, ok := f.r[l.nd]; ok && l.dist+fl.dist < mu {
+					mu = l.dist + fl.dist
+					meet = l.nd
+				}
+			})
+		}
+	}
+	if meet == nil {
+		return nil, math.Inf(1) // no path
+	}
+	// recover forward part of path by tracing via links back to start
+	var path []graph2.Half
+	for l := f.r[meet]; ; l = f.r[l.via] {
+		path = append(path, graph2.Half{l.ed, l.nd})
+		if l.via == nil {
+			break
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	// append backward part by tracing via links on to end
+	for l := b.r[meet]; l.via != nil; l = b.r[l.via] {
+		path = append(path, graph2.Half{l.ed, l.via})
+	}
+	return path, mu
+}
+
+// bdNode holds data for a node reached by one side of a bidirectional
+// search.
+type bdNode struct {
+	nd   graph2.BiHalfNode
+	dist float64           // best known distance from start (or to end)
+	via  graph2.BiHalfNode // previous (or next) node along the path
+	ed   interface{}       // arc between via and nd
+	rx   int               // heap.Remove index, -1 when done
+}
+
+// bdSearch is the state of one side of a bidirectional search.
+type bdSearch struct {
+	r map[graph2.BiHalfNode]*bdNode // all nodes reached
+	h bdHeap                        // nodes in the tentative set
+}
+
+func newBDSearch(nd graph2.BiHalfNode) *bdSearch {
+	p := &bdNode{nd: nd}
+	return &bdSearch{
+		r: map[graph2.BiHalfNode]*bdNode{nd: p},
+		h: bdHeap{p},
+	}
+}
+
+// relax considers a path to nd through the node of from.  If the path
+// is an improvement, relax records it and returns the data for nd.
+// Otherwise it returns nil.
+func (s *bdSearch) relax(from *bdNode, nd graph2.BiHalfNode, ed interface{}) *bdNode {
+	dist := from.dist + ed.(graph2.Weighted).Weight()
+	l, ok := s.r[nd]
+	if !ok {
+		// nd being reached for the first time.
+		l = &bdNode{nd: nd, dist: dist, via: from.nd, ed: ed}
+		s.r[nd] = l
+		heap.Push(&s.h, l)
+		return l
+	}
+	if l.rx < 0 || dist >= l.dist {
+		return nil // nd is done or the path is no help
+	}
+	l.dist = dist
+	l.via = from.nd
+	l.ed = ed
+	heap.Fix(&s.h, l.rx)
+	return l
+}
+
+type bdHeap []*bdNode
+
+// implement container/heap
+func (h bdHeap) Len() int           { return len(h) }
+func (h bdHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h bdHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].rx = i
+	h[j].rx = j
+}
+func (p *bdHeap) Push(x interface{}) {
+	h := *p
+	rx := len(h)
+	h = append(h, x.(*bdNode))
+	h[rx].rx = rx
+	*p = h
+}
+
+func (p *bdHeap) Pop() interface{} {
+	h := *p
+	last := len(h) - 1
+	*p = h[:last]
+	h[last].rx = -1
+	return h[last]
+}
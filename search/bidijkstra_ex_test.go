@@ -0,0 +1,77 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// DijkstraBidirectional requires a node type that implements
+// graph2.BiHalfNode and an edge type that implements graph2.Weighted.
+// Nodes keep lists of both outward and inward arcs.
+
+type (
+	biNode struct {
+		name string            // node name
+		out  []graph2.Half     // outward arcs and nodes they lead to
+		in   []graph2.FromHalf // inward arcs and nodes they lead from
+	}
+	biArc float64
+)
+
+// Two methods implement graph2.BiHalfNode.
+func (n *biNode) VisitAdjHalfs(v graph2.AdjHalfVisitor) {
+	for _, a := range n.out {
+		v(a)
+	}
+}
+func (n *biNode) VisitInHalfs(v graph2.InHalfVisitor) {
+	for _, a := range n.in {
+		v(a)
+	}
+}
+
+// One method implements graph2.Weighted.
+func (a biArc) Weight() float64 {
+	return float64(a)
+}
+
+// Implement fmt.Stringer to make output easy.
+func (n *biNode) String() string { return n.name }
+
+// One more method to make graph construction easy.
+func (n *biNode) link(n2 *biNode, weight int) {
+	n.out = append(n.out, graph2.Half{biArc(weight), n2})
+	n2.in = append(n2.in, graph2.FromHalf{n, biArc(weight)})
+}
+
+func ExampleDijkstraBidirectional() {
+	a := &biNode{name: "a"}
+	b := &biNode{name: "b"}
+	c := &biNode{name: "c"}
+	d := &biNode{name: "d"}
+	e := &biNode{name: "e"}
+	f := &biNode{name: "f"}
+	a.link(b, 7)
+	a.link(c, 9)
+	a.link(f, 14)
+	b.link(c, 10)
+	b.link(d, 15)
+	c.link(d, 11)
+	c.link(f, 2)
+	d.link(e, 6)
+	e.link(f, 9)
+	fmt.Println("Directed graph with 6 nodes, 9 edges")
+
+	path, l := search.DijkstraBidirectional(a, e)
+	fmt.Println(`Shortest path from node "a" to node "e":`, path)
+	fmt.Println("Path length:", l)
+	// Output:
+	// Directed graph with 6 nodes, 9 edges
+	// Shortest path from node "a" to node "e": [{<nil> a} {9 c} {11 d} {6 e}]
+	// Path length: 26
+}
@@ -0,0 +1,115 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+func TestDijkstraBidirectional(t *testing.T) {
+	start, end := r(1000, 3000, 66)
+	_, l1 := search.DijkstraShortestPath(start, end)
+	p2, l2 := search.DijkstraBidirectional(start, end)
+	if math.Abs(l1-l2) > 1e-9 {
+		t.Fatal("length", l2, "DijkstraShortestPath length", l1)
+	}
+	checkBDPath(t, start, end, p2, l2)
+}
+
+// checkBDPath checks that p is a path from start to end of length l.
+func checkBDPath(t *testing.T, start, end *stNode, p []graph2.Half, l float64) {
+	if p[0].To != start || p[len(p)-1].To != end {
+		t.Fatal("path does not connect start and end:", p)
+	}
+	// check that the path is connected and sums to the returned length
+	d := 0.
+	for i := 1; i < len(p); i++ {
+		a := p[i].Ed.(stArc)
+		if a.to != p[i].To {
+			t.Fatal("bad arc in path", p)
+		}
+		found := false
+		for _, nb := range p[i-1].To.(*stNode).nbs {
+			if nb == a {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatal("arc not adjacent to previous node in path", p)
+		}
+		d += a.weight
+	}
+	if math.Abs(d-l) > 1e-9 {
+		t.Fatal("path sums to", d, "returned length", l)
+	}
+}
+
+// randBiGraph returns n nodes with m random arcs, with in-arcs recorded
+// for DijkstraBidirectional.  Graphs are sparse enough that some nodes
+// cannot reach others.
+func randBiGraph(n, m int, seed int64) []*stNode {
+	x := rand.New(rand.NewSource(seed))
+	nodes := make([]*stNode, n)
+	for i := range nodes {
+		nodes[i] = &stNode{name: strconv.Itoa(i)}
+	}
+	for i := 0; i < m; i++ {
+		n1, n2 := nodes[x.Intn(n)], nodes[x.Intn(n)]
+		a := stArc{float64(x.Intn(10)), n2}
+		n1.nbs = append(n1.nbs, a)
+		n2.in = append(n2.in, graph2.FromHalf{n1, a})
+	}
+	return nodes
+}
+
+func TestDijkstraBidirectionalRandom(t *testing.T) {
+	unreachable := 0
+	for seed := int64(1); seed <= 10; seed++ {
+		nodes := randBiGraph(50, 80, seed)
+		x := rand.New(rand.NewSource(seed))
+		for i := 0; i < 30; i++ {
+			start, end := nodes[x.Intn(len(nodes))], nodes[x.Intn(len(nodes))]
+			_, l1 := search.DijkstraShortestPath(start, end)
+			p2, l2 := search.DijkstraBidirectional(start, end)
+			if math.IsInf(l1, 1) {
+				unreachable++
+				if p2 != nil || !math.IsInf(l2, 1) {
+					t.Fatal("seed", seed, start, end, "found", p2, l2, "want no path")
+				}
+				continue
+			}
+			if math.Abs(l1-l2) > 1e-9 {
+				t.Fatal("seed", seed, start, end, "length", l2,
+					"DijkstraShortestPath length", l1)
+			}
+			checkBDPath(t, start, end, p2, l2)
+		}
+	}
+	if unreachable == 0 {
+		t.Fatal("no unreachable targets tested")
+	}
+}
+
+func TestDijkstraBidirectionalNoPath(t *testing.T) {
+	start, _ := r(100, 200, 62)
+	p, l := search.DijkstraBidirectional(start, &stNode{name: "x"})
+	if p != nil || !math.IsInf(l, 1) {
+		t.Fatal("expected no path, got", p, l)
+	}
+}
+
+func BenchmarkBidirectional1e4(b *testing.B) {
+	// 10k nodes
+	start, end := r(1e4, 5e4, 59)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		search.DijkstraBidirectional(start, end)
+	}
+}
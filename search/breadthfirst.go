@@ -120,31 +120,30 @@ func bottomUp(lNum int, lmap bfMap, visit graph2.BF2NodeVisitor, visited bfVMap,
 
 func BreadthFirst1(start graph2.Node, visit graph2.LevelVisitor) (p map[graph2.Node]graph2.Node, ok bool) {
 	lnum := 0
-	visited := map[graph2.Node]graph2.Node{}
+	visited := newNodeMap[graph2.Node, graph2.Node](start)
 	if !visit(start, lnum) {
-		return visited, false
+		return visited.goMap(), false
 	}
-	visited[start] = nil
+	visited.set(start, nil)
 	level := []graph2.Node{start}
 	next := []graph2.Node{}
 	for len(level) > 0 {
 		lnum++
 		for _, v := range level {
 			if !v.VisitAdjNodes(func(n graph2.Node) bool {
-				if _, ok := visited[n]; !ok {
+				if _, ok := visited.get(n); !ok {
 					if !visit(n, lnum) {
 						return false
 					}
-					visited[n] = v
+					visited.set(n, v)
 					next = append(next, n)
 				}
 				return true
 			}) {
-				return visited, false
+				return visited.goMap(), false
 			}
 		}
-		level = next
-		next = level[:0]
+		level, next = next, level[:0]
 	}
-	return visited, true
+	return visited.goMap(), true
 }
@@ -0,0 +1,81 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import (
+	"context"
+	"math"
+
+	"github.com/soniakeys/graph2"
+)
+
+// The functions here are cancellable variants of searches in this package.
+// Each checks its context periodically, every ctxCheck nodes expanded, so
+// that cancellation is noticed promptly without checking the context at
+// every step.  A context already done when a function is called is noticed
+// before any nodes are expanded.
+
+// DijkstraShortestPathContext is DijkstraShortestPath with a context.
+//
+// If ctx is done before the search completes, DijkstraShortestPathContext
+// returns ctx.Err().  If the end node has been reached by then, the path and
+// distance returned are the best found so far.  Otherwise the path is nil
+// and the distance +Inf.
+func DijkstraShortestPathContext(ctx context.Context, start, end graph2.HalfNode) ([]graph2.Half, float64, error) {
+	if start == nil {
+		return nil, math.Inf(1), ctx.Err()
+	}
+	_, path, dist, err := djk(halfNode{start}, halfNode{end}, false,
+		&djkOpt[halfNode, graph2.Weighted]{ctx: ctx})
+	return halfPath(path), dist, err
+}
+
+// DijkstraAllPathsContext is DijkstraAllPaths with a context.
+//
+// If ctx is done before the search completes, DijkstraAllPathsContext
+// returns ctx.Err() along with the tree of nodes with shortest paths
+// determined so far.
+func DijkstraAllPathsContext(ctx context.Context, start graph2.HalfNode) (map[graph2.HalfNode]graph2.FromHalf, error) {
+	if start == nil {
+		return nil, ctx.Err()
+	}
+	ht := halfTree{}
+	tree, _, _, err := djk(halfNode{start}, halfNode{}, true,
+		&djkOpt[halfNode, graph2.Weighted]{ctx: ctx, tree: ht})
+	if tree != nil { // cancelled, or indexed nodes
+		return fromHalfTree(tree), err
+	}
+	return ht, err
+}
+
+// AStarAContext is AStarA with a context.
+//
+// If ctx is done before the search completes, AStarAContext returns
+// ctx.Err().  If the end node has been reached by then, the path and
+// distance returned are the best found so far.  Otherwise the path is nil
+// and the distance +Inf.
+func AStarAContext(ctx context.Context, start, end graph2.EstimateNode) ([]graph2.Half, float64, error) {
+	path, dist, err := astarA(estimateNode{start}, estimateNode{end},
+		&astarOpt[estimateNode, graph2.Weighted]{ctx: ctx})
+	return estimatePath(path), dist, err
+}
+
+// AStarMContext is AStarM with a context.
+//
+// The result on cancellation is as documented for AStarAContext.
+func AStarMContext(ctx context.Context, start, end graph2.EstimateNode) ([]graph2.Half, float64, error) {
+	path, dist, err := astarM(estimateNode{start}, estimateNode{end},
+		&astarOpt[estimateNode, graph2.Weighted]{ctx: ctx})
+	return estimatePath(path), dist, err
+}
+
+// DepthFirstContext is DepthFirst with a context.
+//
+// If ctx is done before the traversal completes, DepthFirstContext stops
+// and returns false and ctx.Err().
+func DepthFirstContext(ctx context.Context, n graph2.Node, v graph2.LevelVisitor) (ok bool, err error) {
+	return depthFirst(ctx, node{n}, func(n node, level int) bool {
+		return v(n.Node, level)
+	})
+}
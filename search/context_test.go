@@ -0,0 +1,110 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// Estimate makes stNode a graph2.EstimateNode.  Arc weights generated by r
+// are Euclidean distances so straight line distance is monotonic.
+func (n *stNode) Estimate(e graph2.EstimateNode) float64 {
+	end := e.(*stNode)
+	return math.Hypot(end.x-n.x, end.y-n.y)
+}
+
+// VisitAdjNodes makes stNode a graph2.Node.
+func (n *stNode) VisitAdjNodes(v graph2.AdjNodeVisitor) bool {
+	for _, a := range n.nbs {
+		if !v(a.to) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestContextCancelled(t *testing.T) {
+	start, end := r(1e4, 5e4, 59)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if p, d, err := search.DijkstraShortestPathContext(ctx, start, end); err != context.Canceled || p != nil || !math.IsInf(d, 1) {
+		t.Fatal("DijkstraShortestPathContext:", len(p), d, err)
+	}
+	if tr, err := search.DijkstraAllPathsContext(ctx, start); err != context.Canceled || len(tr) != 1 { // just start
+		t.Fatal("DijkstraAllPathsContext:", len(tr), err)
+	}
+	if p, d, err := search.AStarAContext(ctx, start, end); err != context.Canceled || p != nil || !math.IsInf(d, 1) {
+		t.Fatal("AStarAContext:", len(p), d, err)
+	}
+	if p, d, err := search.AStarMContext(ctx, start, end); err != context.Canceled || p != nil || !math.IsInf(d, 1) {
+		t.Fatal("AStarMContext:", len(p), d, err)
+	}
+	visited := 0
+	ok, err := search.DepthFirstContext(ctx, start, func(graph2.Node, int) bool {
+		visited++
+		return true
+	})
+	if ok || err != context.Canceled || visited != 0 {
+		t.Fatal("DepthFirstContext:", ok, err, visited)
+	}
+}
+
+func TestContextLive(t *testing.T) {
+	start, end := r(1e4, 5e4, 59)
+	ctx := context.Background()
+	p0, d0 := search.DijkstraShortestPath(start, end)
+	if p0 == nil {
+		t.Fatal("no path in test graph")
+	}
+	check := func(name string, p []graph2.Half, d float64, err error) {
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if d != d0 || len(p) != len(p0) || p[len(p)-1].To != end {
+			t.Fatal(name, "got", len(p), d, "want", len(p0), d0)
+		}
+	}
+	p, d, err := search.DijkstraShortestPathContext(ctx, start, end)
+	check("DijkstraShortestPathContext", p, d, err)
+	p, d, err = search.AStarAContext(ctx, start, end)
+	check("AStarAContext", p, d, err)
+	p, d, err = search.AStarMContext(ctx, start, end)
+	check("AStarMContext", p, d, err)
+
+	tr, err := search.DijkstraAllPathsContext(ctx, start)
+	if err != nil || len(tr) != len(search.DijkstraAllPaths(start)) {
+		t.Fatal("DijkstraAllPathsContext:", len(tr), err)
+	}
+	n0 := 0
+	search.DepthFirst(start, func(graph2.Node, int) bool { n0++; return true })
+	n := 0
+	ok, err := search.DepthFirstContext(ctx, start, func(graph2.Node, int) bool {
+		n++
+		return true
+	})
+	if !ok || err != nil || n != n0 {
+		t.Fatal("DepthFirstContext:", ok, err, n, n0)
+	}
+}
+
+// TestContextPartial cancels a search part way through.
+func TestContextPartial(t *testing.T) {
+	start, _ := r(1e4, 5e4, 59)
+	ctx, cancel := context.WithCancel(context.Background())
+	visited := 0
+	ok, err := search.DepthFirstContext(ctx, start, func(graph2.Node, int) bool {
+		if visited++; visited == 1500 {
+			cancel()
+		}
+		return true
+	})
+	if ok || err != context.Canceled || visited != 2048 {
+		t.Fatal("DepthFirstContext:", ok, err, visited)
+	}
+}
@@ -1,6 +1,10 @@
 package search
 
-import "github.com/soniakeys/graph2"
+import (
+	"context"
+
+	"github.com/soniakeys/graph2"
+)
 
 // DepthFirst traverses nodes in depth first order.
 //
@@ -8,20 +12,51 @@ import "github.com/soniakeys/graph2"
 // If the visitor function returns false for any node, DepthFirst stops and
 // returns false immediately.  DepthFirst returns true otherwise.
 func DepthFirst(n graph2.Node, v graph2.LevelVisitor) (ok bool) {
-	m := map[graph2.Node]struct{}{}
-	var r func(graph2.Node, int) bool
-	r = func(n graph2.Node, level int) bool {
-		if _, ok := m[n]; ok {
+	return DepthFirstOf(node{n}, func(n node, level int) bool {
+		return v(n.Node, level)
+	})
+}
+
+// DepthFirstOf is a type parameterized DepthFirst.
+func DepthFirstOf[N graph2.NodeOf[N]](n N, v graph2.LevelVisitorOf[N]) (ok bool) {
+	ok, _ = depthFirst(nil, n, v)
+	return
+}
+
+// depthFirst implements DepthFirst.  If ctx is not nil it is checked every
+// ctxCheck nodes visited.  If ctx is done, depthFirst stops and returns
+// false and the context error.
+func depthFirst[N graph2.NodeOf[N]](ctx context.Context, n N, v graph2.LevelVisitorOf[N]) (ok bool, err error) {
+	visited := 0
+	level := 0 // depth of search stack
+	ok = DepthFirstEventsOf([]N{n}, DFEventsOf[N]{
+		Discover: func(n N, _ int) bool {
+			if ctx != nil && visited%ctxCheck == 0 {
+				if err = ctx.Err(); err != nil {
+					return false
+				}
+			}
+			visited++
+			if !v(n, level) {
+				return false
+			}
+			level++
 			return true
-		}
-		if !v(n, level) {
-			return false
-		}
-		m[n] = struct{}{}
-		level++
-		return n.VisitAdjNodes(func(n graph2.Node) bool {
-			return r(n, level)
-		})
-	}
-	return r(n, 0)
+		},
+		Finish: func(N, int) bool {
+			level--
+			return true
+		},
+	})
+	return ok, err
+}
+
+// node adapts a graph2.Node to graph2.NodeOf.  It allows the interface{}
+// based API to share code with the type parameterized API.
+type node struct{ graph2.Node }
+
+func (n node) VisitAdjNodesOf(v graph2.AdjNodeVisitorOf[node]) bool {
+	return n.VisitAdjNodes(func(nb graph2.Node) bool {
+		return v(node{nb})
+	})
 }
@@ -0,0 +1,55 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// dfOfNode satisfies graph2.NodeOf[*dfOfNode].
+type dfOfNode struct {
+	num int
+	nbs []*dfOfNode
+}
+
+// VisitAdjNodesOf is the only method needed to satisfy the constraint.
+func (n *dfOfNode) VisitAdjNodesOf(v graph2.AdjNodeVisitorOf[*dfOfNode]) bool {
+	for _, nb := range n.nbs {
+		if !v(nb) {
+			return false
+		}
+	}
+	return true
+}
+
+func ExampleDepthFirstOf() {
+	n5 := &dfOfNode{num: 5}
+	n6 := &dfOfNode{num: 6}
+	n7 := &dfOfNode{num: 7}
+	n8 := &dfOfNode{num: 8}
+	n9 := &dfOfNode{num: 9}
+	n5.nbs = []*dfOfNode{n6, n7, n9}
+	n6.nbs = []*dfOfNode{n7}
+	n7.nbs = []*dfOfNode{n5, n7, n8}
+	fmt.Println("Node  Level")
+	// n is typed *dfOfNode, no assertion is needed.
+	v := func(n *dfOfNode, level int) bool {
+		if n.num == 9 {
+			return false
+		}
+		fmt.Println(n.num, "    ", level)
+		return true
+	}
+	fmt.Println(search.DepthFirstOf(n5, v))
+	// Output:
+	// Node  Level
+	// 5      0
+	// 6      1
+	// 7      2
+	// 8      3
+	// false
+}
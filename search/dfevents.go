@@ -0,0 +1,154 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import (
+	"github.com/soniakeys/graph2"
+)
+
+// EdgeKind classifies an arc followed in a depth first search.
+type EdgeKind int
+
+const (
+	// TreeEdge leads to a node not yet discovered.  Tree edges form the
+	// depth first search forest.
+	TreeEdge EdgeKind = iota
+	// BackEdge leads to a node discovered but not yet finished, that is,
+	// to an ancestor in the search tree or to the node itself.  A back
+	// edge closes a cycle.
+	BackEdge
+	// ForwardEdge leads to a finished descendant in the search tree.
+	ForwardEdge
+	// CrossEdge leads to a finished node that is not a descendant.
+	CrossEdge
+)
+
+var edgeKindNames = [...]string{"tree", "back", "forward", "cross"}
+
+func (k EdgeKind) String() string { return edgeKindNames[k] }
+
+// DFEvents holds event functions for DepthFirstEvents.  Any may be nil.
+// If any returns false, the search stops.
+type DFEvents struct {
+	// Discover is called when a node is first reached.
+	Discover func(n graph2.Node, time int) (ok bool)
+	// Finish is called when all nodes adjacent to n have been searched.
+	Finish func(n graph2.Node, time int) (ok bool)
+	// Edge is called for each arc, before the node the arc leads to is
+	// discovered in the case of a tree edge.
+	Edge func(from, to graph2.Node, kind EdgeKind) (ok bool)
+}
+
+// DepthFirstEvents traverses nodes in depth first order, reporting events
+// of the traversal.
+//
+// The search starts from each of roots in turn, skipping roots already
+// discovered.  It uses an explicit stack rather than recursion so is safe
+// for graphs with long paths.
+//
+// Discover and Finish events receive timestamps from a single clock that
+// advances with each discovery and each finish.  Times start at 1.  A node
+// discovered at time d and finished at time f is an ancestor in the search
+// forest of exactly the nodes with times nested between d and f.
+//
+// DepthFirstEvents returns false if an event function returned false,
+// true otherwise.
+func DepthFirstEvents(roots []graph2.Node, ev DFEvents) (ok bool) {
+	r := make([]node, len(roots))
+	for i, nd := range roots {
+		r[i] = node{nd}
+	}
+	var e DFEventsOf[node]
+	if ev.Discover != nil {
+		e.Discover = func(n node, time int) bool { return ev.Discover(n.Node, time) }
+	}
+	if ev.Finish != nil {
+		e.Finish = func(n node, time int) bool { return ev.Finish(n.Node, time) }
+	}
+	if ev.Edge != nil {
+		e.Edge = func(from, to node, kind EdgeKind) bool {
+			return ev.Edge(from.Node, to.Node, kind)
+		}
+	}
+	return DepthFirstEventsOf(r, e)
+}
+
+// DFEventsOf is a type parameterized DFEvents.
+type DFEventsOf[N any] struct {
+	Discover func(n N, time int) (ok bool)
+	Finish   func(n N, time int) (ok bool)
+	Edge     func(from, to N, kind EdgeKind) (ok bool)
+}
+
+// DepthFirstEventsOf is a type parameterized DepthFirstEvents.
+func DepthFirstEventsOf[N graph2.NodeOf[N]](roots []N, ev DFEventsOf[N]) (ok bool) {
+	// dfFrame represents a node in the process of being searched.
+	type dfFrame struct {
+		nd  N
+		nbs []N // adjacent nodes
+		i   int // index of next adjacent node to search
+	}
+	var (
+		disc  = map[N]int{}      // discovery times
+		fin   = map[N]struct{}{} // finished nodes
+		stack []dfFrame          // replaces recursion
+		time  = 0
+	)
+	discover := func(nd N) bool {
+		time++
+		disc[nd] = time
+		if ev.Discover != nil && !ev.Discover(nd, time) {
+			return false
+		}
+		f := dfFrame{nd: nd}
+		nd.VisitAdjNodesOf(func(nb N) bool {
+			f.nbs = append(f.nbs, nb)
+			return true
+		})
+		stack = append(stack, f)
+		return true
+	}
+	for _, root := range roots {
+		if _, ok := disc[root]; ok {
+			continue
+		}
+		if !discover(root) {
+			return false
+		}
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			if f.i < len(f.nbs) {
+				from, to := f.nd, f.nbs[f.i]
+				f.i++
+				kind := TreeEdge
+				if t, ok := disc[to]; ok {
+					switch _, done := fin[to]; {
+					case !done:
+						kind = BackEdge
+					case disc[from] < t:
+						kind = ForwardEdge
+					default:
+						kind = CrossEdge
+					}
+				}
+				if ev.Edge != nil && !ev.Edge(from, to, kind) {
+					return false
+				}
+				if kind == TreeEdge && !discover(to) {
+					return false
+				}
+				continue
+			}
+			// all adjacent nodes searched.
+			nd := f.nd
+			stack = stack[:len(stack)-1]
+			time++
+			fin[nd] = struct{}{}
+			if ev.Finish != nil && !ev.Finish(nd, time) {
+				return false
+			}
+		}
+	}
+	return true
+}
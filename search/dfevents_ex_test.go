@@ -0,0 +1,47 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+func ExampleDepthFirstEvents() {
+	g := dfGraph(5, [2]int{0, 1}, [2]int{0, 2}, [2]int{1, 2}, [2]int{2, 0},
+		[2]int{3, 2}, [2]int{3, 4})
+	search.DepthFirstEvents(g, search.DFEvents{
+		Discover: func(n graph2.Node, time int) bool {
+			fmt.Println(time, "discover", n)
+			return true
+		},
+		Finish: func(n graph2.Node, time int) bool {
+			fmt.Println(time, "finish", n)
+			return true
+		},
+		Edge: func(from, to graph2.Node, kind search.EdgeKind) bool {
+			fmt.Println("   ", from, "->", to, kind)
+			return true
+		},
+	})
+	// Output:
+	// 1 discover 0
+	//     0 -> 1 tree
+	// 2 discover 1
+	//     1 -> 2 tree
+	// 3 discover 2
+	//     2 -> 0 back
+	// 4 finish 2
+	// 5 finish 1
+	//     0 -> 2 forward
+	// 6 finish 0
+	// 7 discover 3
+	//     3 -> 2 cross
+	//     3 -> 4 tree
+	// 8 discover 4
+	// 9 finish 4
+	// 10 finish 3
+}
@@ -0,0 +1,129 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// TestDepthFirstEventsLong searches a path much longer than would be
+// practical with recursion on a small stack.
+func TestDepthFirstEventsLong(t *testing.T) {
+	const n = 1e5
+	arcs := make([][2]int, n-1)
+	for i := range arcs {
+		arcs[i] = [2]int{i, i + 1}
+	}
+	g := dfGraph(n, arcs...)
+	tree := 0
+	search.DepthFirstEvents(g[:1], search.DFEvents{
+		Edge: func(_, _ graph2.Node, kind search.EdgeKind) bool {
+			if kind == search.TreeEdge {
+				tree++
+			}
+			return true
+		},
+	})
+	if tree != n-1 {
+		t.Fatal(tree, "tree edges, want", n-1)
+	}
+}
+
+// TestDepthFirstEventsTimes checks the parenthesis property of timestamps
+// and edge classification against it.
+func TestDepthFirstEventsTimes(t *testing.T) {
+	x := rand.New(rand.NewSource(7))
+	const n = 200
+	arcs := make([][2]int, 600)
+	for i := range arcs {
+		arcs[i] = [2]int{x.Intn(n), x.Intn(n)}
+	}
+	g := dfGraph(n, arcs...)
+	disc := map[graph2.Node]int{}
+	fin := map[graph2.Node]int{}
+	type arc struct {
+		from, to graph2.Node
+		kind     search.EdgeKind
+	}
+	var edges []arc
+	last := 0
+	clock := func(time int) {
+		if time != last+1 {
+			t.Fatal("time", time, "after", last)
+		}
+		last = time
+	}
+	search.DepthFirstEvents(g, search.DFEvents{
+		Discover: func(nd graph2.Node, time int) bool {
+			clock(time)
+			disc[nd] = time
+			return true
+		},
+		Finish: func(nd graph2.Node, time int) bool {
+			clock(time)
+			fin[nd] = time
+			return true
+		},
+		Edge: func(from, to graph2.Node, kind search.EdgeKind) bool {
+			edges = append(edges, arc{from, to, kind})
+			return true
+		},
+	})
+	if len(disc) != n || len(fin) != n || last != 2*n {
+		t.Fatal("discovered", len(disc), "finished", len(fin), "time", last)
+	}
+	if len(edges) != len(arcs) {
+		t.Fatal(len(edges), "edges, want", len(arcs))
+	}
+	for _, e := range edges {
+		du, fu := disc[e.from], fin[e.from]
+		dv, fv := disc[e.to], fin[e.to]
+		var want search.EdgeKind
+		switch {
+		case dv <= du && fu <= fv:
+			want = search.BackEdge // to is an ancestor, or from itself
+		case du < dv && fv < fu:
+			// to is a descendant.  a tree edge if from is its parent.
+			want = search.ForwardEdge
+			if e.kind == search.TreeEdge {
+				want = search.TreeEdge
+			}
+		case fv < du:
+			want = search.CrossEdge
+		default:
+			t.Fatal("arc", e.from, e.to, "violates timestamp rules")
+		}
+		if e.kind != want {
+			t.Fatal(e.from, "->", e.to, e.kind, "want", want)
+		}
+	}
+	// tree edges form a forest with one parent per non-root
+	parent := map[graph2.Node]graph2.Node{}
+	for _, e := range edges {
+		if e.kind == search.TreeEdge {
+			if _, ok := parent[e.to]; ok {
+				t.Fatal("two tree edges to", e.to)
+			}
+			parent[e.to] = e.from
+		}
+	}
+}
+
+func TestDepthFirstEventsStop(t *testing.T) {
+	g := dfGraph(3, [2]int{0, 1}, [2]int{1, 2})
+	n := 0
+	ok := search.DepthFirstEvents(g, search.DFEvents{
+		Discover: func(graph2.Node, int) bool {
+			n++
+			return n < 2
+		},
+	})
+	if ok || n != 2 {
+		t.Fatal(ok, n)
+	}
+}
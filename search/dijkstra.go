@@ -5,9 +5,11 @@ package search
 
 import (
 	"container/heap"
+	"context"
 	"math"
 
 	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/internal"
 )
 
 // DijkstraShortestPath finds a shortest path between two nodes.
@@ -27,7 +29,20 @@ import (
 // from the start node, the returned Half list will be nil and the path
 // length +Inf.
 func DijkstraShortestPath(start, end graph2.HalfNode) ([]graph2.Half, float64) {
-	_, path, dist := djk(start, end, false)
+	if start == nil {
+		return nil, math.Inf(1)
+	}
+	path, dist := DijkstraShortestPathOf(halfNode{start}, halfNode{end})
+	return halfPath(path), dist
+}
+
+// DijkstraShortestPathOf is a type parameterized DijkstraShortestPath.
+//
+// Node type N and edge type E are checked at compile time.  The edge member
+// of the first element of the returned path will be the zero value of E.
+// Otherwise the result is as documented for DijkstraShortestPath.
+func DijkstraShortestPathOf[N graph2.HalfNodeOf[N, E], E graph2.Weighted](start, end N) ([]graph2.HalfOf[N, E], float64) {
+	_, path, dist, _ := djk(start, end, false, nil)
 	return path, dist
 }
 
@@ -45,12 +60,97 @@ func DijkstraShortestPath(start, end graph2.HalfNode) ([]graph2.Half, float64) {
 // node along the shortest path.  The start node is included in the result,
 // with a zero value element.
 func DijkstraAllPaths(start graph2.HalfNode) map[graph2.HalfNode]graph2.FromHalf {
-	tree, _, _ := djk(start, nil, true)
+	if start == nil {
+		return nil
+	}
+	ht := halfTree{}
+	tree, _, _, _ := djk(halfNode{start}, halfNode{}, true,
+		&djkOpt[halfNode, graph2.Weighted]{tree: ht})
+	if tree != nil { // indexed nodes, tree not built in ht
+		return fromHalfTree(tree)
+	}
+	return ht
+}
+
+// DijkstraAllPathsOf is a type parameterized DijkstraAllPaths.
+//
+// The result is as documented for DijkstraAllPaths.  The start node is
+// included with a zero value element.
+func DijkstraAllPathsOf[N graph2.HalfNodeOf[N, E], E graph2.Weighted](start N) map[N]graph2.FromHalfOf[N, E] {
+	var end N
+	tree, _, _, _ := djk(start, end, true, nil)
+	return tree
+}
+
+// halfNode adapts a graph2.HalfNode with graph2.Weighted edges to
+// graph2.HalfNodeOf.  It allows the interface{} based API to share code
+// with the type parameterized API.
+type halfNode struct{ graph2.HalfNode }
+
+func (n halfNode) VisitAdjHalfsOf(v graph2.AdjHalfVisitorOf[halfNode, graph2.Weighted]) {
+	n.VisitAdjHalfs(halfVisitor(v))
+}
+
+func (halfNode) adjVisitor(v graph2.AdjHalfVisitorOf[halfNode, graph2.Weighted]) func(halfNode) {
+	hv := halfVisitor(v)
+	return func(n halfNode) { n.VisitAdjHalfs(hv) }
+}
+
+// halfVisitor adapts v to visit graph2.Half values.
+func halfVisitor(v graph2.AdjHalfVisitorOf[halfNode, graph2.Weighted]) graph2.AdjHalfVisitor {
+	return func(h graph2.Half) {
+		v(graph2.HalfOf[halfNode, graph2.Weighted]{
+			h.Ed.(graph2.Weighted), halfNode{h.To}})
+	}
+}
+
+// halfPath converts a path of adapted nodes back to a graph2.Half path.
+func halfPath(p []graph2.HalfOf[halfNode, graph2.Weighted]) []graph2.Half {
+	if p == nil {
+		return nil
+	}
+	path := make([]graph2.Half, len(p))
+	for i, h := range p {
+		path[i] = graph2.Half{h.Ed, h.To.HalfNode}
+	}
+	return path
+}
+
+// halfTree is a search tree of the interface{} based API.  As external
+// storage for djk, it lets djk build the result of DijkstraAllPaths directly.
+type halfTree map[graph2.HalfNode]graph2.FromHalf
+
+func (t halfTree) get(n halfNode) (graph2.FromHalfOf[halfNode, graph2.Weighted], bool) {
+	f, ok := t[n.HalfNode]
+	w, _ := f.Ed.(graph2.Weighted) // nil for the start node
+	return graph2.FromHalfOf[halfNode, graph2.Weighted]{halfNode{f.From}, w}, ok
+}
+
+func (t halfTree) set(n halfNode, f graph2.FromHalfOf[halfNode, graph2.Weighted]) {
+	t[n.HalfNode] = graph2.FromHalf{f.From.HalfNode, f.Ed}
+}
+
+func (t halfTree) del(n halfNode) { delete(t, n.HalfNode) }
+func (t halfTree) len() int       { return len(t) }
+
+func (t halfTree) each(f func(halfNode, graph2.FromHalfOf[halfNode, graph2.Weighted])) {
+	for nd := range t {
+		fh, _ := t.get(halfNode{nd})
+		f(halfNode{nd}, fh)
+	}
+}
+
+// fromHalfTree converts a tree of adapted nodes back to graph2.FromHalfs.
+func fromHalfTree(t map[halfNode]graph2.FromHalfOf[halfNode, graph2.Weighted]) map[graph2.HalfNode]graph2.FromHalf {
+	tree := make(map[graph2.HalfNode]graph2.FromHalf, len(t))
+	for nd, f := range t {
+		tree[nd.HalfNode] = graph2.FromHalf{f.From.HalfNode, f.Ed}
+	}
 	return tree
 }
 
 // dijkstra holds data per node that is needed by the algorithm.  The
-// data is associated with nodes with a map in the search struct (below.)
+// data is associated with nodes with a nodeMap in djk.
 // Data is not added to the map until a node is "visited" or reached by
 // the search.  Field tx represents the status of a node which may be
 // unvisited, tentative, or done.  tx = 0 represents unvisited so that
@@ -68,120 +168,310 @@ type dijkstra struct {
 }
 
 // tentPath holds additional data for a node in the "tentative set".
-type tentPath struct {
+type tentPath[N any] struct {
 	dist float64 // tentative path distance
 	n    int     // number of nodes in path
 	rx   int     // heap.Remove index
-	nd   graph2.HalfNode
+	nd   N
 }
 
-type tentHeap struct {
-	pool []tentPath
+type tentHeap[N any] struct {
+	pool []tentPath[N]
 	heap []int // values are indexes into pool
 	free []int // values are indexes into pool
 }
 
 // search implements container/heap
-func (h tentHeap) Len() int { return len(h.heap) }
-func (h tentHeap) Less(i, j int) bool {
+func (h tentHeap[N]) Len() int { return len(h.heap) }
+func (h tentHeap[N]) Less(i, j int) bool {
 	return h.pool[h.heap[i]].dist < h.pool[h.heap[j]].dist
 }
-func (h tentHeap) Swap(i, j int) {
+func (h tentHeap[N]) Swap(i, j int) {
 	h.heap[i], h.heap[j] = h.heap[j], h.heap[i]
 	h.pool[h.heap[i]].rx = i
 	h.pool[h.heap[j]].rx = j
 }
-func (h *tentHeap) Push(x interface{}) {
+func (h *tentHeap[N]) Push(x interface{}) {
 	tx := x.(int)
 	h.pool[tx].rx = len(h.heap)
 	h.heap = append(h.heap, tx)
 }
-func (h *tentHeap) Pop() interface{} {
+func (h *tentHeap[N]) Pop() interface{} {
 	last := len(h.heap) - 1
 	tx := h.heap[last]
 	h.heap = h.heap[:last]
 	return tx
 }
 
-func djk(start, end graph2.HalfNode, all bool) (map[graph2.HalfNode]graph2.FromHalf, []graph2.Half, float64) {
-	if start == nil {
-		return nil, nil, math.Inf(1)
-	}
-	current := start
-	cd := dijkstra{tx: -1} // mark start done.  it skips the heap.
-	d := map[graph2.HalfNode]dijkstra{start: cd}
-	prev := map[graph2.HalfNode]graph2.FromHalf{start: graph2.FromHalf{}}
-	ct := tentPath{n: 1} // path length 1 for start node
-	h := &tentHeap{
-		pool: make([]tentPath, 1)} // zero element unused
-	for {
-		if current == end { // single path search complete
-			distance := ct.dist
-			// recover path by tracing prev links
-			i := ct.n
-			path := make([]graph2.Half, i)
-			for i > 0 {
-				i--
-				from := prev[current]
-				path[i].Ed = from.Ed
-				path[i].To = current
-				current = from.From
+// djkOpt holds optional parameters for djk.
+type djkOpt[N comparable, E any] struct {
+	// nodeMask holds nodes that the search must not enter.
+	nodeMask map[N]struct{}
+	// arcMask holds, by the node they lead from, arcs that the search must
+	// not follow.  Arcs are matched by both the arc and the node it leads
+	// to.  Arcs that are not comparable, or that hold values that are not
+	// comparable, never match.
+	arcMask map[N][]graph2.HalfOf[N, E]
+	// ctx, if not nil, is checked every ctxCheck nodes settled.
+	ctx context.Context
+	// sources, if not nil, replaces the start node.  The search starts
+	// from each source with the source's initial offset distance.
+	sources map[N]float64
+	// targets, if not nil, replaces the end node.  A single path search
+	// completes at the first target done.
+	targets map[N]struct{}
+	// lim bounds the search.
+	lim Limits
+	// wantTree, if true, has a single path search that finds its path
+	// return the tree of nodes done as well.
+	wantTree bool
+	// tree, if not nil, is external storage for the tree of previous
+	// nodes.  djk then returns a nil tree where it would return the
+	// complete tree, unless nodes implement graph2.IndexedNode.
+	tree extMap[N, graph2.FromHalfOf[N, E]]
+}
+
+// limited returns true if o has any limit set.
+func (o *djkOpt[N, E]) limited() bool {
+	return o != nil && o.lim != Limits{}
+}
+
+// ctxCheck is the number of nodes searches expand between checks of
+// a context.
+const ctxCheck = 1024
+
+// isEnd returns true if nd completes a single path search, that is, if nd
+// is end or, if o has targets, if nd is a target.
+func (o *djkOpt[N, E]) isEnd(nd, end N) bool {
+	if o != nil && o.targets != nil {
+		_, ok := o.targets[nd]
+		return ok
+	}
+	return nd == end
+}
+
+// masked returns true if the arc a from node nd is masked.
+func (o *djkOpt[N, E]) masked(nd N, a graph2.HalfOf[N, E]) bool {
+	if o.nodeMask == nil && o.arcMask == nil {
+		return false
+	}
+	if _, ok := o.nodeMask[a.To]; ok {
+		return true
+	}
+	for _, m := range o.arcMask[nd] {
+		if m.To == a.To && internal.Equal(m.Ed, a.Ed) {
+			return true
+		}
+	}
+	return false
+}
+
+// djk implements Dijkstra's algorithm.  If all is true, end is ignored and
+// the search continues until all nodes reachable from start are done.
+// Argument o may be nil.
+//
+// If o has sources, start is ignored and the search starts from all sources.
+// If o has targets, end is ignored and a single path search completes at
+// the first target done.
+//
+// If a context of o is done before the search completes, djk returns the
+// context error.  The tree returned then has just the nodes done so far and
+// the path returned is the tentative path to end, if end has been reached.
+//
+// If limits of o are reached before end is done, djk returns the tree of
+// nodes done within the limits, a nil path and +Inf.  Nodes with shortest
+// paths exceeding o.lim.MaxHops are left out of the tree but are expanded
+// so that distances remain those of shortest paths.  The search stops when
+// no tentative node is within MaxHops.
+func djk[N graph2.HalfNodeOf[N, E], E graph2.Weighted](start, end N, all bool, o *djkOpt[N, E]) (map[N]graph2.FromHalfOf[N, E], []graph2.HalfOf[N, E], float64, error) {
+	nd0 := start // any node of the graph, to size per node state
+	if o != nil && o.sources != nil {
+		for src := range o.sources {
+			nd0 = src
+			break
+		}
+	}
+	d := newNodeMap[N, dijkstra](nd0)
+	var prev *nodeMap[N, graph2.FromHalfOf[N, E]]
+	if o != nil && o.tree != nil {
+		prev = newExtNodeMap(nd0, o.tree)
+	} else {
+		prev = newNodeMap[N, graph2.FromHalfOf[N, E]](nd0)
+	}
+	h := &tentHeap[N]{
+		pool: make([]tentPath[N], 1)} // zero element unused
+	inTree := 0  // number of done nodes in tree
+	hopTent := 0 // number of tentative nodes within o.lim.MaxHops
+	hopLim := o.limited() && o.lim.MaxHops > 0
+	// reach is the first visit to node nd, by a path of n nodes and
+	// distance dist.  from is the previous node and arc along the path.
+	reach := func(nd N, from graph2.FromHalfOf[N, E], dist float64, n int) {
+		var tx int
+		// first find a place for tentPath data
+		if len(h.free) == 0 {
+			// nothing on the free list, extend the pool.
+			tx = len(h.pool)
+			h.pool = append(h.pool, tentPath[N]{
+				nd:   nd,
+				dist: dist,
+				n:    n})
+		} else { // reuse
+			last := len(h.free) - 1
+			tx = h.free[last]
+			h.free = h.free[:last]
+			h.pool[tx] = tentPath[N]{
+				nd:   nd,
+				dist: dist,
+				n:    n}
+		}
+		// push path data to heap
+		if hopLim && n-1 <= o.lim.MaxHops {
+			hopTent++
+		}
+		prev.set(nd, from)
+		d.set(nd, dijkstra{tx: tx})
+		heap.Push(h, tx)
+	}
+	var current N
+	var ct tentPath[N]
+	// visit relaxes arc a from current.  it is created once per search
+	// rather than once per node expanded.
+	visit := func(a graph2.HalfOf[N, E]) {
+		nd, _ := d.get(a.To)
+		if nd.tx < 0 {
+			return // skip nodes already done
+		}
+		if o != nil && o.masked(current, a) {
+			return
+		}
+		dist := ct.dist + a.Ed.Weight()
+		if nd.tx == 0 { // first visit to this node.
+			reach(a.To, graph2.FromHalfOf[N, E]{current, a.Ed}, dist, ct.n+1)
+			return
+		}
+		// node already in tentative set
+		nt := &h.pool[nd.tx]
+		if dist >= nt.dist {
+			return // it's no help
+		}
+		// the path through current to this node is shorter than some
+		// other path to this node.  record new path data and reheap.
+		nt.dist = dist
+		if hopLim {
+			hopTent += o.lim.hopDelta(nt.n, ct.n+1)
+		}
+		nt.n = ct.n + 1
+		prev.set(a.To, graph2.FromHalfOf[N, E]{current, a.Ed})
+		heap.Fix(h, nt.rx)
+	}
+	var expand func(N)
+	if va, ok := any(start).(adjVisitorOf[N, E]); ok {
+		expand = va.adjVisitor(visit)
+	} else {
+		expand = func(nd N) { nd.VisitAdjHalfsOf(visit) }
+	}
+	multi := o != nil && o.sources != nil
+	if multi {
+		for src, offset := range o.sources {
+			reach(src, graph2.FromHalfOf[N, E]{}, offset, 1)
+		}
+	} else {
+		// the single start node skips the heap.
+		current = start
+		ct = tentPath[N]{nd: start, n: 1}
+		prev.set(start, graph2.FromHalfOf[N, E]{})
+		if hopLim {
+			hopTent = 1 // as if reached
+		}
+	}
+	for settled := 0; ; settled++ {
+		if settled > 0 || multi {
+			if hopLim && hopTent == 0 {
+				// no path within the limit can reach any further nodes.
+				return doneTree(prev, d), nil, math.Inf(1), nil
 			}
-			return nil, path, distance // success
+			if len(h.heap) == 0 {
+				return prev.goMap(), nil, math.Inf(1), nil
+			}
+			// new current is node with smallest tentative distance
+			ctx := heap.Pop(h).(int)
+			ct = h.pool[ctx]
+			current = ct.nd
+			if o.limited() && o.lim.MaxDist > 0 && ct.dist > o.lim.MaxDist {
+				// all remaining tentative nodes are beyond the limit.
+				return doneTree(prev, d), nil, math.Inf(1), nil
+			}
+			h.free = append(h.free, ctx) // recycle tentPath struct
 		}
-		current.VisitAdjHalfs(func(a graph2.Half) {
-			nd := d[a.To]
-			if nd.tx < 0 {
-				return // skip nodes already done
+		d.set(current, dijkstra{tx: -1}) // done
+		switch {
+		case !hopLim:
+			inTree++
+		case ct.n-1 <= o.lim.MaxHops:
+			hopTent--
+			inTree++
+		default:
+			// too many hops.  leave current out of the tree.
+			prev.del(current)
+			if !all && o.isEnd(current, end) {
+				return doneTree(prev, d), nil, math.Inf(1), nil
 			}
-			dist := ct.dist + a.Ed.(graph2.Weighted).Weight()
-			if nd.tx > 0 { // node already in tentative set
-				nt := &h.pool[nd.tx]
-				if dist >= nt.dist {
-					return // it's no help
+		}
+		if !all && o.isEnd(current, end) { // single path search complete
+			var tree map[N]graph2.FromHalfOf[N, E]
+			if o != nil && o.wantTree {
+				tree = doneTree(prev, d)
+			}
+			return tree, tracePath(prev, current, ct.n), ct.dist, nil // success
+		}
+		if o != nil && o.ctx != nil && settled%ctxCheck == 0 {
+			if err := o.ctx.Err(); err != nil {
+				if all {
+					return doneTree(prev, d), nil, math.Inf(1), err
 				}
-				// the path through current to this node is shorter than some
-				// other path to this node.  record new path data and reheap.
-				nt.dist = dist
-				nt.n = ct.n + 1
-				prev[a.To] = graph2.FromHalf{current, a.Ed}
-				d[a.To] = nd
-				heap.Fix(h, nt.rx)
-			} else { // nd.tx was zero. this is the first visit to this node.
-				// first find a place for tentPath data
-				if len(h.free) == 0 {
-					// nothing on the free list, extend the pool.
-					nd.tx = len(h.pool)
-					h.pool = append(h.pool, tentPath{
-						nd:   a.To,
-						dist: dist,
-						n:    ct.n + 1})
-				} else { // reuse
-					last := len(h.free) - 1
-					nd.tx = h.free[last]
-					h.free = h.free[:last]
-					h.pool[nd.tx] = tentPath{
-						nd:   a.To,
-						dist: dist,
-						n:    ct.n + 1}
+				if et, _ := d.get(end); o.targets == nil && et.tx > 0 {
+					tp := h.pool[et.tx]
+					return nil, tracePath(prev, end, tp.n), tp.dist, err
 				}
-				// push path data to heap
-				prev[a.To] = graph2.FromHalf{current, a.Ed}
-				d[a.To] = nd
-				heap.Push(h, nd.tx)
+				return nil, nil, math.Inf(1), err
 			}
-		})
-		if len(h.heap) == 0 {
-			//			return stRoot, nil, math.Inf(1)
-			return prev, nil, math.Inf(1)
-		}
-		// new current is node with smallest tentative distance
-		ctx := heap.Pop(h).(int)
-		ct = h.pool[ctx]
-		current = ct.nd
-		cd = d[current]
-		h.free = append(h.free, ctx) // recycle tentPath struct
-		cd.tx = -1                   // done
-		d[current] = cd              // store the -1
+		}
+		if o.limited() && o.lim.MaxSettled > 0 && inTree >= o.lim.MaxSettled {
+			return doneTree(prev, d), nil, math.Inf(1), nil // limit reached
+		}
+		expand(current)
+	}
+}
+
+// adjVisitorOf is implemented by node types that can adapt a visitor once
+// for a whole search, rather than once for each node expanded.  The
+// adapters of the interface{} based API implement it.
+type adjVisitorOf[N, E any] interface {
+	// adjVisitor returns a function that calls VisitAdjHalfsOf(v) for
+	// a node.
+	adjVisitor(v graph2.AdjHalfVisitorOf[N, E]) func(N)
+}
+
+// tracePath recovers the path of n nodes ending at nd by tracing prev links.
+func tracePath[N comparable, E any](prev *nodeMap[N, graph2.FromHalfOf[N, E]], nd N, n int) []graph2.HalfOf[N, E] {
+	path := make([]graph2.HalfOf[N, E], n)
+	for n > 0 {
+		n--
+		from, _ := prev.get(nd)
+		path[n].Ed = from.Ed
+		path[n].To = nd
+		nd = from.From
 	}
+	return path
+}
+
+// doneTree returns the part of the tree prev with nodes that are done.
+func doneTree[N comparable, E any](prev *nodeMap[N, graph2.FromHalfOf[N, E]], d *nodeMap[N, dijkstra]) map[N]graph2.FromHalfOf[N, E] {
+	t := map[N]graph2.FromHalfOf[N, E]{}
+	prev.each(func(nd N, from graph2.FromHalfOf[N, E]) {
+		if dn, _ := d.get(nd); dn.tx < 0 {
+			t[nd] = from
+		}
+	})
+	return t
 }
@@ -0,0 +1,87 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// DijkstraShortestPathOf requires a node type that satisfies
+// graph2.HalfNodeOf and an edge type that implements graph2.Weighted.
+// Node and edge types are checked at compile time.
+
+type (
+	ofNode struct {
+		name string                          // node name
+		nbs  []graph2.HalfOf[*ofNode, ofArc] // adjacent arcs and nodes
+	}
+	ofArc float64
+)
+
+// One method satisfies graph2.HalfNodeOf[*ofNode, ofArc].
+func (n *ofNode) VisitAdjHalfsOf(v graph2.AdjHalfVisitorOf[*ofNode, ofArc]) {
+	for _, a := range n.nbs {
+		v(a)
+	}
+}
+
+// One method implements graph2.Weighted.
+func (a ofArc) Weight() float64 {
+	return float64(a)
+}
+
+// Implement fmt.Stringer to make output easy.
+func (n *ofNode) String() string { return n.name }
+
+// One more method to make graph construction easy.
+func (n *ofNode) link(n2 *ofNode, weight int) {
+	n.nbs = append(n.nbs, graph2.HalfOf[*ofNode, ofArc]{ofArc(weight), n2})
+}
+
+func ExampleDijkstraShortestPathOf() {
+	a := &ofNode{name: "a"}
+	b := &ofNode{name: "b"}
+	c := &ofNode{name: "c"}
+	d := &ofNode{name: "d"}
+	e := &ofNode{name: "e"}
+	f := &ofNode{name: "f"}
+	a.link(b, 7)
+	a.link(c, 9)
+	a.link(f, 14)
+	b.link(c, 10)
+	b.link(d, 15)
+	c.link(d, 11)
+	c.link(f, 2)
+	d.link(e, 6)
+	e.link(f, 9)
+
+	// Type parameters are inferred.  The path elements are typed
+	// graph2.HalfOf[*ofNode, ofArc] so no assertions are needed.
+	path, l := search.DijkstraShortestPathOf(a, e)
+	for _, h := range path[1:] {
+		fmt.Printf("%g to %s\n", float64(h.Ed), h.To.name)
+	}
+	fmt.Println("Path length:", l)
+	// Output:
+	// 9 to c
+	// 11 to d
+	// 6 to e
+	// Path length: 26
+}
+
+func ExampleDijkstraAllPathsOf() {
+	a := &ofNode{name: "a"}
+	b := &ofNode{name: "b"}
+	c := &ofNode{name: "c"}
+	a.link(b, 7)
+	a.link(c, 9)
+	b.link(c, 1)
+	from := search.DijkstraAllPathsOf(a)
+	fmt.Println(from[c].From.name, from[c].Ed)
+	// Output:
+	// b 1
+}
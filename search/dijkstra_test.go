@@ -35,6 +35,7 @@ type stNode struct {
 	name string
 	x, y float64
 	nbs  []stArc
+	in   []graph2.FromHalf
 }
 
 type stArc struct {
@@ -48,6 +49,12 @@ func (n *stNode) VisitAdjHalfs(v graph2.AdjHalfVisitor) {
 	}
 }
 
+func (n *stNode) VisitInHalfs(v graph2.InHalfVisitor) {
+	for _, a := range n.in {
+		v(a)
+	}
+}
+
 func (n *stNode) String() string { return n.name }
 
 func (a stArc) Weight() float64 { return float64(a.weight) }
@@ -115,7 +122,9 @@ generateArcs:
 				continue generateArcs // no parallel arcs
 			}
 		}
-		nd1.nbs = append(nd1.nbs, stArc{dist, nd2})
+		a := stArc{dist, nd2}
+		nd1.nbs = append(nd1.nbs, a)
+		nd2.in = append(nd2.in, graph2.FromHalf{nd1, a})
 		i++
 	}
 	return
@@ -6,7 +6,14 @@
 //
 // Dijkstra's algorithm finds the shortest path between two nodes in a
 // directed or undirected graph with weighted edges.  The edge weights
-// must be non-negative.
+// must be non-negative.  A bidirectional variant searches from both ends
+// of the path at once for graphs where nodes also provide inward arcs.
+// A multi-source variant finds the nearest of a set of target nodes to any
+// of a set of source nodes.  Voronoi similarly searches from a set of seed
+// nodes to partition a graph into cells around the seeds.
+//
+// The Bellman-Ford algorithm also finds shortest paths but allows negative
+// edge weights.  It detects and reports negative cycles.
 //
 // Algorithm A and A* optimize the shortest path search using a heuristic
 // estimate of the distance to the end node.  A heuristic is termed admissable
@@ -21,5 +28,30 @@
 // must be less than or equal to the edge weight AB plus the estimate from
 // B.  The package has a separate function optimized for monotonic graphs.
 //
-// Search requires Go 1.2.
+// DepthFirstEvents is a depth first search reporting discover and finish
+// events with timestamps and classifying arcs as tree, back, forward, or
+// cross edges.  It serves as a basis for algorithms such as topological
+// sorting.
+//
+// Functions with names ending in "Of" are type parameterized versions of
+// the functions without the suffix.  They operate on graphs through the
+// constraints of package graph2 such as graph2.HalfNodeOf, so that node and
+// edge types are checked at compile time.  The functions without the suffix
+// are thin wrappers that adapt the interface{} based types of graph2 to the
+// type parameterized functions.
+//
+// Functions with names ending in "Context" take a context.Context and can
+// be cancelled.  On cancellation they return the context error along with
+// any partial result, such as the best path found so far.  Similarly,
+// functions with names ending in "Limit" take Limits bounding the distance,
+// number of nodes settled, or hop count of a search and return the search
+// tree truncated at the limits.
+//
+// Dijkstra, A*, and BreadthFirst1 searches keep per node state in maps.
+// If the start node implements graph2.IndexedNode, they keep it instead in
+// slices and bitsets indexed by NodeID, which is faster for large graphs.
+//
+// Search requires Go 1.20.  The interface{} based functions instantiate the
+// type parameterized functions with node types that hold interfaces, and
+// such types satisfy comparable only as of Go 1.20.
 package search
@@ -0,0 +1,198 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import (
+	"math/bits"
+
+	"github.com/soniakeys/graph2"
+)
+
+// indexed returns n as a graph2.IndexedNode, if it implements the
+// interface.  Nodes wrapped by the adapters of the interface{} based API
+// are unwrapped.
+func indexed[N any](n N) (graph2.IndexedNode, bool) {
+	// the conversion any(n) in the type switch does not escape, so it does
+	// not allocate.  a type assertion on the adapted node does not either.
+	var x interface{}
+	switch a := any(n).(type) {
+	case halfNode:
+		x = a.HalfNode
+	case estimateNode:
+		x = a.EstimateNode
+	case node:
+		x = a.Node
+	default:
+		x = any(n)
+	}
+	ix, ok := x.(graph2.IndexedNode)
+	return ix, ok
+}
+
+// nodeID returns the graph2.IndexedNode index of n.
+func nodeID[N any](n N) int {
+	x, _ := indexed(n)
+	return x.NodeID()
+}
+
+// bitset is a set of small non-negative integers.
+type bitset []uint64
+
+func newBitset(n int) bitset { return make(bitset, (n+63)/64) }
+
+func (b bitset) has(i int) bool { return b[i>>6]&(1<<(uint(i)&63)) != 0 }
+func (b bitset) add(i int)      { b[i>>6] |= 1 << (uint(i) & 63) }
+func (b bitset) remove(i int)   { b[i>>6] &^= 1 << (uint(i) & 63) }
+
+// nodeMap associates values with nodes for the duration of a search.
+//
+// It is backed by a Go map in general.  If the node given to newNodeMap
+// implements graph2.IndexedNode, it is backed instead by slices indexed by
+// NodeID, with a bitset of nodes present.  A search may also supply
+// external storage with newExtNodeMap.
+type nodeMap[N comparable, V any] struct {
+	m     map[N]V
+	ext   extMap[N, V]
+	nodes []N // nodes by index, where present
+	vals  []V // values by index, where present
+	has   bitset
+	n     int // number of nodes present, for slice backing
+}
+
+// newNodeMap returns an empty nodeMap for nodes of the graph of nd.
+func newNodeMap[N comparable, V any](nd N) *nodeMap[N, V] {
+	x, ok := indexed(nd)
+	if !ok {
+		return &nodeMap[N, V]{m: map[N]V{}}
+	}
+	n := x.NumNodes()
+	return &nodeMap[N, V]{
+		nodes: make([]N, n),
+		vals:  make([]V, n),
+		has:   newBitset(n),
+	}
+}
+
+// extMap is external storage for a nodeMap.  It allows a search to build
+// a result of the interface{} based API directly, rather than converting
+// the result of the type parameterized search.
+type extMap[N comparable, V any] interface {
+	get(N) (V, bool)
+	set(N, V)
+	del(N)
+	len() int
+	each(func(N, V))
+}
+
+// newExtNodeMap returns a nodeMap backed by ext, unless nd implements
+// graph2.IndexedNode.  In that case it returns a nodeMap backed by slices
+// and the search result must be taken from the nodeMap rather than ext.
+func newExtNodeMap[N comparable, V any](nd N, ext extMap[N, V]) *nodeMap[N, V] {
+	m := newNodeMap[N, V](nd)
+	if m.m != nil {
+		m.m, m.ext = nil, ext
+	}
+	return m
+}
+
+// get returns the value for nd, or the zero value and false if nd is not
+// present.
+func (m *nodeMap[N, V]) get(nd N) (v V, ok bool) {
+	if m.m != nil {
+		v, ok = m.m[nd]
+		return
+	}
+	if m.ext != nil {
+		return m.ext.get(nd)
+	}
+	i := nodeID(nd)
+	if !m.has.has(i) {
+		return
+	}
+	return m.vals[i], true
+}
+
+// set sets the value for nd.
+func (m *nodeMap[N, V]) set(nd N, v V) {
+	if m.m != nil {
+		m.m[nd] = v
+		return
+	}
+	if m.ext != nil {
+		m.ext.set(nd, v)
+		return
+	}
+	i := nodeID(nd)
+	if !m.has.has(i) {
+		m.has.add(i)
+		m.nodes[i] = nd
+		m.n++
+	}
+	m.vals[i] = v
+}
+
+// del removes nd.
+func (m *nodeMap[N, V]) del(nd N) {
+	if m.m != nil {
+		delete(m.m, nd)
+		return
+	}
+	if m.ext != nil {
+		m.ext.del(nd)
+		return
+	}
+	i := nodeID(nd)
+	if m.has.has(i) {
+		m.has.remove(i)
+		var zn N
+		var zv V
+		m.nodes[i], m.vals[i] = zn, zv
+		m.n--
+	}
+}
+
+// len returns the number of nodes present.
+func (m *nodeMap[N, V]) len() int {
+	if m.m != nil {
+		return len(m.m)
+	}
+	if m.ext != nil {
+		return m.ext.len()
+	}
+	return m.n
+}
+
+// each calls f for each node present and its value.
+func (m *nodeMap[N, V]) each(f func(N, V)) {
+	if m.m != nil {
+		for nd, v := range m.m {
+			f(nd, v)
+		}
+		return
+	}
+	if m.ext != nil {
+		m.ext.each(f)
+		return
+	}
+	for w, b := range m.has {
+		for ; b != 0; b &= b - 1 {
+			i := w*64 + bits.TrailingZeros64(b)
+			f(m.nodes[i], m.vals[i])
+		}
+	}
+}
+
+// goMap returns the contents of m as a Go map.  For map backing this is
+// the backing map itself.  For external backing it is nil.
+func (m *nodeMap[N, V]) goMap() map[N]V {
+	if m.m != nil {
+		return m.m
+	}
+	if m.ext != nil {
+		return nil
+	}
+	r := make(map[N]V, m.n)
+	m.each(func(nd N, v V) { r[nd] = v })
+	return r
+}
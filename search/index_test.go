@@ -0,0 +1,164 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// gridNode is a node of a grid graph with arcs to its four neighbors.
+// Arc weights are at least 1 so that Manhattan distance is a monotonic
+// estimate.
+type gridNode struct {
+	x, y int
+	nbs  []graph2.Half
+}
+
+func (n *gridNode) VisitAdjHalfs(v graph2.AdjHalfVisitor) {
+	for _, h := range n.nbs {
+		v(h)
+	}
+}
+
+func (n *gridNode) VisitAdjNodes(v graph2.AdjNodeVisitor) bool {
+	for _, h := range n.nbs {
+		if !v(h.To.(graph2.Node)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *gridNode) Estimate(e graph2.EstimateNode) float64 {
+	var m *gridNode
+	switch e := e.(type) {
+	case *gridNode:
+		m = e
+	case *ixGridNode:
+		m = &e.gridNode
+	}
+	return math.Abs(float64(m.x-n.x)) + math.Abs(float64(m.y-n.y))
+}
+
+// ixGridNode is a gridNode implementing graph2.IndexedNode.
+type ixGridNode struct {
+	gridNode
+	id, num int
+}
+
+func (n *ixGridNode) NodeID() int   { return n.id }
+func (n *ixGridNode) NumNodes() int { return n.num }
+
+// grid returns the corner nodes of a w by w grid graph with random arc
+// weights.  Nodes are *ixGridNodes if indexed is true, *gridNodes otherwise.
+func grid(w int, indexed bool) (start, end graph2.EstimateNode) {
+	r := rand.New(rand.NewSource(1))
+	nodes := make([]graph2.EstimateNode, w*w)
+	gn := make([]*gridNode, w*w)
+	for i := range nodes {
+		if indexed {
+			n := &ixGridNode{gridNode{x: i % w, y: i / w}, i, w * w}
+			nodes[i], gn[i] = n, &n.gridNode
+		} else {
+			n := &gridNode{x: i % w, y: i / w}
+			nodes[i], gn[i] = n, n
+		}
+	}
+	for _, n := range gn {
+		for _, d := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			x, y := n.x+d[0], n.y+d[1]
+			if x >= 0 && x < w && y >= 0 && y < w {
+				n.nbs = append(n.nbs, graph2.Half{
+					gridArc(1 + r.Float64()), nodes[y*w+x]})
+			}
+		}
+	}
+	return nodes[0], nodes[w*w-1]
+}
+
+type gridArc float64
+
+func (a gridArc) Weight() float64 { return float64(a) }
+
+func TestIndexed(t *testing.T) {
+	ms, me := grid(30, false)
+	is, ie := grid(30, true)
+	_, md := search.DijkstraShortestPath(ms, me)
+	_, id := search.DijkstraShortestPath(is, ie)
+	if md != id {
+		t.Fatalf("Dijkstra: %g indexed, %g map", id, md)
+	}
+	if mt, it := search.DijkstraAllPaths(ms), search.DijkstraAllPaths(is); len(mt) != len(it) {
+		t.Fatalf("DijkstraAllPaths: %d nodes indexed, %d map", len(it), len(mt))
+	}
+	for _, f := range []func(s, e graph2.EstimateNode) ([]graph2.Half, float64){
+		search.AStarA, search.AStarM,
+	} {
+		mp, md := f(ms, me)
+		ip, id := f(is, ie)
+		if md != id || len(mp) != len(ip) {
+			t.Fatalf("A*: %g, %d nodes indexed, %g, %d nodes map",
+				id, len(ip), md, len(mp))
+		}
+	}
+	levels := func(s graph2.Node) (m map[graph2.Node]graph2.Node, sum int) {
+		m, _ = search.BreadthFirst1(s, func(_ graph2.Node, l int) bool {
+			sum += l
+			return true
+		})
+		return
+	}
+	mm, ml := levels(ms.(graph2.Node))
+	im, il := levels(is.(graph2.Node))
+	if len(mm) != 900 || len(im) != 900 || ml != il {
+		t.Fatalf("BreadthFirst1: %d, %d indexed, %d, %d map",
+			len(im), il, len(mm), ml)
+	}
+	for nd, from := range im {
+		if _, ok := im[from]; from != nil && !ok {
+			t.Fatal("BreadthFirst1 parent of", nd, "not in tree")
+		}
+	}
+}
+
+func benchIndexed(b *testing.B, f func(s, e graph2.EstimateNode)) {
+	for _, c := range []struct {
+		name    string
+		indexed bool
+	}{{"map", false}, {"indexed", true}} {
+		s, e := grid(300, c.indexed)
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				f(s, e)
+			}
+		})
+	}
+}
+
+func BenchmarkIndexedDijkstraAllPaths(b *testing.B) {
+	benchIndexed(b, func(s, _ graph2.EstimateNode) { search.DijkstraAllPaths(s) })
+}
+
+func BenchmarkIndexedDijkstraShortestPath(b *testing.B) {
+	benchIndexed(b, func(s, e graph2.EstimateNode) { search.DijkstraShortestPath(s, e) })
+}
+
+func BenchmarkIndexedAStarA(b *testing.B) {
+	benchIndexed(b, func(s, e graph2.EstimateNode) { search.AStarA(s, e) })
+}
+
+func BenchmarkIndexedAStarM(b *testing.B) {
+	benchIndexed(b, func(s, e graph2.EstimateNode) { search.AStarM(s, e) })
+}
+
+func BenchmarkIndexedBreadthFirst1(b *testing.B) {
+	benchIndexed(b, func(s, _ graph2.EstimateNode) {
+		search.BreadthFirst1(s.(graph2.Node), func(graph2.Node, int) bool { return true })
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import (
+	"math"
+
+	"github.com/soniakeys/graph2"
+)
+
+// Limits bounds the extent of a search.  A zero value for any field means
+// no limit.
+type Limits struct {
+	// MaxDist is the maximum path distance from the start node.
+	MaxDist float64
+	// MaxSettled is the maximum number of nodes settled, that is, nodes
+	// with paths determined and added to the search tree, including the
+	// start node.
+	MaxSettled int
+	// MaxHops is the maximum number of arcs in a path.
+	MaxHops int
+}
+
+// hopDelta returns the change in the number of paths within l.MaxHops
+// when a path of n0 nodes is replaced by one of n nodes.
+func (l Limits) hopDelta(n0, n int) int {
+	switch in0, in := n0-1 <= l.MaxHops, n-1 <= l.MaxHops; {
+	case in && !in0:
+		return 1
+	case in0 && !in:
+		return -1
+	}
+	return 0
+}
+
+// DijkstraShortestPathLimit is DijkstraShortestPath bounded by limits.
+//
+// The path and distance returned are those of DijkstraShortestPath if the
+// path is found within the limits.  Otherwise the path is nil and the
+// distance +Inf.  Also returned is the tree of nodes settled, as documented
+// for DijkstraAllPathsLimit.
+func DijkstraShortestPathLimit(start, end graph2.HalfNode, lim Limits) (path []graph2.Half, dist float64, tree map[graph2.HalfNode]graph2.FromHalf) {
+	if start == nil {
+		return nil, math.Inf(1), nil
+	}
+	t, p, dist, _ := djk(halfNode{start}, halfNode{end}, false,
+		&djkOpt[halfNode, graph2.Weighted]{lim: lim, wantTree: true})
+	return halfPath(p), dist, fromHalfTree(t)
+}
+
+// DijkstraAllPathsLimit is DijkstraAllPaths bounded by limits.
+//
+// The result is the shortest path tree of DijkstraAllPaths truncated at
+// the limits.  Nodes farther than lim.MaxDist or with shortest paths of
+// more than lim.MaxHops arcs are left out.  If lim.MaxSettled is reached,
+// the tree holds the lim.MaxSettled nodes nearest the start node.
+func DijkstraAllPathsLimit(start graph2.HalfNode, lim Limits) map[graph2.HalfNode]graph2.FromHalf {
+	if start == nil {
+		return nil
+	}
+	tree, _, _, _ := djk(halfNode{start}, halfNode{}, true,
+		&djkOpt[halfNode, graph2.Weighted]{lim: lim})
+	return fromHalfTree(tree)
+}
+
+// AStarALimit is AStarA bounded by limits.
+//
+// Paths farther than lim.MaxDist are not followed.  Nodes reached by paths
+// of more than lim.MaxHops arcs are left out of the tree and the end node
+// is not found if its shortest path has more than lim.MaxHops arcs.  The
+// search stops after expanding lim.MaxSettled nodes.
+//
+// The path and distance returned are those of AStarA if the path is found
+// within the limits.  Otherwise the path is nil and the distance +Inf.
+// Also returned is the tree of nodes expanded, each with the half arc
+// leading to it along the path by which it was last expanded.  The start
+// node is included with a zero value element.
+func AStarALimit(start, end graph2.EstimateNode, lim Limits) (path []graph2.Half, dist float64, tree map[graph2.HalfNode]graph2.FromHalf) {
+	o := &astarOpt[estimateNode, graph2.Weighted]{
+		lim:  lim,
+		tree: map[estimateNode]graph2.FromHalfOf[estimateNode, graph2.Weighted]{},
+	}
+	p, dist, _ := astarA(estimateNode{start}, estimateNode{end}, o)
+	return estimatePath(p), dist, estimateTree(o.tree)
+}
+
+// AStarMLimit is AStarM bounded by limits.
+//
+// Limits and results are as documented for AStarALimit.
+func AStarMLimit(start, end graph2.EstimateNode, lim Limits) (path []graph2.Half, dist float64, tree map[graph2.HalfNode]graph2.FromHalf) {
+	o := &astarOpt[estimateNode, graph2.Weighted]{
+		lim:  lim,
+		tree: map[estimateNode]graph2.FromHalfOf[estimateNode, graph2.Weighted]{},
+	}
+	p, dist, _ := astarM(estimateNode{start}, estimateNode{end}, o)
+	return estimatePath(p), dist, estimateTree(o.tree)
+}
+
+// estimateTree converts a tree of adapted nodes back to graph2.FromHalfs.
+func estimateTree(t map[estimateNode]graph2.FromHalfOf[estimateNode, graph2.Weighted]) map[graph2.HalfNode]graph2.FromHalf {
+	tree := make(map[graph2.HalfNode]graph2.FromHalf, len(t))
+	for nd, f := range t {
+		tree[nd.EstimateNode] = graph2.FromHalf{f.From.EstimateNode, f.Ed}
+	}
+	return tree
+}
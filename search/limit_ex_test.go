@@ -0,0 +1,46 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/soniakeys/graph2/search"
+)
+
+// DijkstraAllPathsLimit uses the same node and arc types as DijkstraAllPaths.
+
+func ExampleDijkstraAllPathsLimit() {
+	a := &dapNode{name: "a"}
+	b := &dapNode{name: "b"}
+	c := &dapNode{name: "c"}
+	d := &dapNode{name: "d"}
+	e := &dapNode{name: "e"}
+	f := &dapNode{name: "f"}
+	a.link(b, 7)
+	a.link(c, 9)
+	a.link(f, 14)
+	b.link(c, 10)
+	b.link(d, 15)
+	c.link(d, 11)
+	c.link(f, 2)
+	d.link(e, 6)
+	e.link(f, 9)
+	// everything within distance 12
+	from := search.DijkstraAllPathsLimit(a, search.Limits{MaxDist: 12})
+	as := make([]string, 0, len(from))
+	for nd, fh := range from {
+		as = append(as, fmt.Sprint(fh.From, " ", nd))
+	}
+	sort.Strings(as)
+	for _, s := range as {
+		fmt.Println(s)
+	}
+	// Output:
+	// <nil> a
+	// a b
+	// a c
+	// c f
+}
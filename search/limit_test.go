@@ -0,0 +1,144 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// treeDist computes path distance and hop count of node nd in tree.
+func treeDist(tree map[graph2.HalfNode]graph2.FromHalf, nd graph2.HalfNode) (dist float64, hops int) {
+	for fh := tree[nd]; fh.From != nil; fh = tree[fh.From] {
+		dist += fh.Ed.(graph2.Weighted).Weight()
+		hops++
+	}
+	return
+}
+
+func TestDijkstraAllPathsLimit(t *testing.T) {
+	start, _ := r(1000, 3000, 66)
+	full := search.DijkstraAllPaths(start)
+	check := func(lim search.Limits, want func(dist float64, hops int) bool) {
+		tree := search.DijkstraAllPathsLimit(start, lim)
+		n := 0
+		for nd := range full {
+			dist, hops := treeDist(full, nd)
+			_, in := tree[nd]
+			if !want(dist, hops) {
+				if in {
+					t.Fatal(lim, "unexpected node", nd, dist, hops)
+				}
+				continue
+			}
+			n++
+			if !in {
+				t.Fatal(lim, "missing node", nd, dist, hops)
+			}
+			if tree[nd] != full[nd] {
+				t.Fatal(lim, "different path to", nd)
+			}
+		}
+		if len(tree) != n {
+			t.Fatal(lim, "tree size", len(tree), "want", n)
+		}
+	}
+	check(search.Limits{MaxDist: .2}, func(d float64, _ int) bool {
+		return d <= .2
+	})
+	check(search.Limits{MaxHops: 3}, func(_ float64, h int) bool {
+		return h <= 3
+	})
+	check(search.Limits{MaxDist: .3, MaxHops: 4}, func(d float64, h int) bool {
+		return d <= .3 && h <= 4
+	})
+	// settled limit keeps the nearest nodes
+	tree := search.DijkstraAllPathsLimit(start, search.Limits{MaxSettled: 50})
+	if len(tree) != 50 {
+		t.Fatal("MaxSettled 50, got", len(tree))
+	}
+	max := 0.
+	for nd := range tree {
+		if d, _ := treeDist(tree, nd); d > max {
+			max = d
+		}
+	}
+	for nd := range full {
+		if _, in := tree[nd]; !in {
+			if d, _ := treeDist(full, nd); d < max {
+				t.Fatal("node nearer than", max, "left out:", nd, d)
+			}
+		}
+	}
+}
+
+func TestShortestPathLimit(t *testing.T) {
+	start, end := r(1000, 3000, 66)
+	p0, d0 := search.DijkstraShortestPath(start, end)
+	if p0 == nil {
+		t.Fatal("no path in test graph")
+	}
+	hops := len(p0) - 1
+	type sp func(start, end *stNode, lim search.Limits) ([]graph2.Half, float64, map[graph2.HalfNode]graph2.FromHalf)
+	for _, f := range []struct {
+		name string
+		f    sp
+	}{
+		{"DijkstraShortestPathLimit", func(s, e *stNode, lim search.Limits) ([]graph2.Half, float64, map[graph2.HalfNode]graph2.FromHalf) {
+			return search.DijkstraShortestPathLimit(s, e, lim)
+		}},
+		{"AStarALimit", func(s, e *stNode, lim search.Limits) ([]graph2.Half, float64, map[graph2.HalfNode]graph2.FromHalf) {
+			return search.AStarALimit(s, e, lim)
+		}},
+		{"AStarMLimit", func(s, e *stNode, lim search.Limits) ([]graph2.Half, float64, map[graph2.HalfNode]graph2.FromHalf) {
+			return search.AStarMLimit(s, e, lim)
+		}},
+	} {
+		for _, tc := range []struct {
+			lim   search.Limits
+			found bool
+		}{
+			{search.Limits{}, true},
+			{search.Limits{MaxDist: d0}, true},
+			{search.Limits{MaxDist: d0 * .99}, false},
+			{search.Limits{MaxHops: hops}, true},
+			{search.Limits{MaxHops: hops - 1}, false},
+			{search.Limits{MaxSettled: 2}, false},
+		} {
+			p, d, tree := f.f(start, end, tc.lim)
+			if !tc.found {
+				if p != nil || !math.IsInf(d, 1) {
+					t.Fatal(f.name, tc.lim, "expected no path, got", len(p), d)
+				}
+			} else if d != d0 || len(p) != len(p0) {
+				t.Fatal(f.name, tc.lim, "got", len(p), d, "want", len(p0), d0)
+			}
+			if _, ok := tree[start]; !ok {
+				t.Fatal(f.name, tc.lim, "start not in tree")
+			}
+			if tc.lim.MaxSettled > 0 && len(tree) > tc.lim.MaxSettled {
+				t.Fatal(f.name, tc.lim, "tree size", len(tree))
+			}
+		}
+	}
+}
+
+func TestShortestPathLimitZero(t *testing.T) {
+	// zero Limits give the result of DijkstraShortestPath, with the tree
+	start, end := r(100, 200, 62)
+	p0, d0 := search.DijkstraShortestPath(start, end)
+	p, d, tree := search.DijkstraShortestPathLimit(start, end, search.Limits{})
+	if p0 == nil || d != d0 || len(p) != len(p0) {
+		t.Fatal("got", len(p), d, "want", len(p0), d0)
+	}
+	if len(tree) < len(p) {
+		t.Fatal("tree of", len(tree), "nodes for path of", len(p))
+	}
+	if td, hops := treeDist(tree, end); td != d0 || hops != len(p)-1 {
+		t.Fatal("tree path to end", td, hops, "want", d0, len(p)-1)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import (
+	"math"
+
+	"github.com/soniakeys/graph2"
+)
+
+// DijkstraNearest finds a shortest path from any of a set of source nodes
+// to any of a set of target nodes.
+//
+// It runs a single Dijkstra search starting from all sources at once.
+// Keys of map sources are source nodes, values are initial offset distances
+// of the sources.  Use zero offsets for sources with no offset.  An offset
+// might represent the cost of starting from a source, for example.  Offsets
+// may be negative but must not be an Inf or NaN.  Keys of map targets are
+// target nodes.  A node may be both a source and a target.
+//
+// Nodes and edges must otherwise satisfy requirements documented for
+// DijkstraShortestPath.
+//
+// Returned are the nearest target, the source the path to it starts from,
+// the path, and the path length including the offset of the source.  As
+// for DijkstraShortestPath, the first element of the path is the source
+// node with a nil edge.  If no target can be reached, target and source
+// are nil, path is nil, and dist is +Inf.
+func DijkstraNearest(sources map[graph2.HalfNode]float64, targets map[graph2.HalfNode]struct{}) (target, source graph2.HalfNode, path []graph2.Half, dist float64) {
+	s := make(map[halfNode]float64, len(sources))
+	for nd, offset := range sources {
+		s[halfNode{nd}] = offset
+	}
+	t := make(map[halfNode]struct{}, len(targets))
+	for nd := range targets {
+		t[halfNode{nd}] = struct{}{}
+	}
+	tg, src, p, dist := DijkstraNearestOf(s, t)
+	return tg.HalfNode, src.HalfNode, halfPath(p), dist
+}
+
+// DijkstraNearestOf is a type parameterized DijkstraNearest.
+//
+// If no target can be reached, target and source are zero values of N.
+// Otherwise the result is as documented for DijkstraNearest.
+func DijkstraNearestOf[N graph2.HalfNodeOf[N, E], E graph2.Weighted](sources map[N]float64, targets map[N]struct{}) (target, source N, path []graph2.HalfOf[N, E], dist float64) {
+	if len(sources) == 0 || len(targets) == 0 {
+		return target, source, nil, math.Inf(1)
+	}
+	var zero N
+	_, path, dist, _ = djk(zero, zero, false,
+		&djkOpt[N, E]{sources: sources, targets: targets})
+	if path == nil {
+		return target, source, nil, dist
+	}
+	return path[len(path)-1].To, path[0].To, path, dist
+}
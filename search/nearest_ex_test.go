@@ -0,0 +1,41 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// DijkstraNearest uses the same node and arc types as DijkstraShortestPath.
+
+func ExampleDijkstraNearest() {
+	c := &dspNode{name: "c"}
+	d := &dspNode{name: "d"}
+	e := &dspNode{name: "e"}
+	f := &dspNode{name: "f"}
+	g := &dspNode{name: "g"}
+	h := &dspNode{name: "h"}
+	c.link(d, 3)
+	c.link(e, 2)
+	d.link(f, 4)
+	e.link(d, 1)
+	e.link(f, 2)
+	e.link(g, 3)
+	f.link(g, 2)
+	f.link(h, 1)
+	g.link(h, 2)
+	// two facilities, d and g.  d has a start up cost.
+	sources := map[graph2.HalfNode]float64{d: 3, g: 0}
+	// which of f and h is nearest to either facility?
+	targets := map[graph2.HalfNode]struct{}{f: {}, h: {}}
+	target, source, path, dist := search.DijkstraNearest(sources, targets)
+	fmt.Println(target, source, dist)
+	fmt.Println(path)
+	// Output:
+	// h g 2
+	// [{<nil> g} {2 h}]
+}
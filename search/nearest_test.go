@@ -0,0 +1,69 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+func TestDijkstraNearest(t *testing.T) {
+	start, _ := r(1000, 3000, 66)
+	// collect nodes reachable from start for picking sources and targets
+	var nodes []graph2.HalfNode
+	for nd := range search.DijkstraAllPaths(start) {
+		nodes = append(nodes, nd)
+	}
+	x := rand.New(rand.NewSource(3))
+	for i := 0; i < 20; i++ {
+		sources := map[graph2.HalfNode]float64{}
+		targets := map[graph2.HalfNode]struct{}{}
+		for j := 0; j < 4; j++ {
+			sources[nodes[x.Intn(len(nodes))]] = x.Float64() * .5
+			targets[nodes[x.Intn(len(nodes))]] = struct{}{}
+		}
+		// brute force over all source-target pairs
+		want := math.Inf(1)
+		for s, off := range sources {
+			for tg := range targets {
+				if _, d := search.DijkstraShortestPath(s, tg); d+off < want {
+					want = d + off
+				}
+			}
+		}
+		tg, src, p, d := search.DijkstraNearest(sources, targets)
+		if math.IsInf(want, 1) {
+			if p != nil || !math.IsInf(d, 1) {
+				t.Fatal("expected no path, got", p, d)
+			}
+			continue
+		}
+		if math.Abs(d-want) > 1e-12 {
+			t.Fatal("dist", d, "want", want)
+		}
+		if p[0].To != src || p[len(p)-1].To != tg {
+			t.Fatal("path ends", p[0].To, p[len(p)-1].To, "source, target", src, tg)
+		}
+		if _, ok := targets[tg]; !ok {
+			t.Fatal(tg, "not a target")
+		}
+		pd := sources[src]
+		for _, h := range p[1:] {
+			pd += h.Ed.(graph2.Weighted).Weight()
+		}
+		if math.Abs(pd-d) > 1e-12 {
+			t.Fatal("path length", pd, "dist", d)
+		}
+	}
+	// a node both source and target
+	if tg, src, p, d := search.DijkstraNearest(
+		map[graph2.HalfNode]float64{start: 1},
+		map[graph2.HalfNode]struct{}{start: {}}); tg != start || src != start || len(p) != 1 || d != 1 {
+		t.Fatal("source as target:", tg, src, p, d)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import "github.com/soniakeys/graph2"
+
+// StronglyConnected finds the strongly connected components of a directed
+// graph.
+//
+// The graph is searched starting from each of the argument nodes in turn.
+// Nodes reachable from the argument nodes are included in the result as
+// well.
+//
+// The implementation is Tarjan's algorithm, written with an explicit stack
+// rather than recursion so that long paths do not exhaust the call stack.
+// Components are returned in reverse topological order.  That is, if there
+// is an arc from a node in one component to a node in another, the other
+// component appears earlier in the result.  A component with more than one
+// node, or with a single node having an arc to itself, contains a cycle.
+func StronglyConnected(nodes []graph2.Node) [][]graph2.Node {
+	var (
+		t     = map[graph2.Node]*sccNode{}
+		stack []graph2.Node // Tarjan's node stack
+		call  []sccFrame    // replaces recursion
+		comps [][]graph2.Node
+		index = 0
+	)
+	push := func(nd graph2.Node) {
+		index++
+		t[nd] = &sccNode{index: index, low: index, onStack: true}
+		stack = append(stack, nd)
+		f := sccFrame{nd: nd}
+		nd.VisitAdjNodes(func(nb graph2.Node) bool {
+			f.nbs = append(f.nbs, nb)
+			return true
+		})
+		call = append(call, f)
+	}
+	for _, root := range nodes {
+		if _, ok := t[root]; ok {
+			continue
+		}
+		push(root)
+		for len(call) > 0 {
+			f := &call[len(call)-1]
+			fs := t[f.nd]
+			if f.i < len(f.nbs) {
+				nb := f.nbs[f.i]
+				f.i++
+				ns, ok := t[nb]
+				if !ok {
+					push(nb) // "recurse"
+				} else if ns.onStack && ns.index < fs.low {
+					fs.low = ns.index
+				}
+				continue
+			}
+			// all neighbors of f.nd are done.
+			call = call[:len(call)-1]
+			if fs.low == fs.index {
+				// f.nd is the root of a component.  pop it from the stack.
+				var c []graph2.Node
+				for {
+					last := len(stack) - 1
+					nd := stack[last]
+					stack = stack[:last]
+					t[nd].onStack = false
+					c = append(c, nd)
+					if nd == f.nd {
+						break
+					}
+				}
+				comps = append(comps, c)
+			}
+			if len(call) > 0 {
+				// "return" low link to caller
+				if ps := t[call[len(call)-1].nd]; fs.low < ps.low {
+					ps.low = fs.low
+				}
+			}
+		}
+	}
+	return comps
+}
+
+// sccNode holds data per node needed by Tarjan's algorithm.
+type sccNode struct {
+	index   int // order of discovery, 1 based
+	low     int // lowest index reachable
+	onStack bool
+}
+
+// sccFrame represents a node in the process of being searched.
+type sccFrame struct {
+	nd  graph2.Node
+	nbs []graph2.Node // adjacent nodes
+	i   int           // index of next adjacent node to search
+}
@@ -0,0 +1,42 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// StronglyConnected uses the same node type as DepthFirst.
+
+func (n *dfNode) String() string { return fmt.Sprint(n.num) }
+
+func ExampleStronglyConnected() {
+	n := make([]*dfNode, 8)
+	for i := range n {
+		n[i] = &dfNode{num: i}
+	}
+	link := func(n1 *dfNode, n2 ...*dfNode) {
+		for _, nd := range n2 {
+			n1.nbs = append(n1.nbs, nd)
+		}
+	}
+	link(n[0], n[1])
+	link(n[1], n[2], n[4], n[5])
+	link(n[2], n[3], n[6])
+	link(n[3], n[2], n[7])
+	link(n[4], n[0], n[5])
+	link(n[5], n[6])
+	link(n[6], n[5])
+	link(n[7], n[3], n[6])
+	for _, c := range search.StronglyConnected([]graph2.Node{n[0]}) {
+		fmt.Println(c)
+	}
+	// Output:
+	// [5 6]
+	// [7 3 2]
+	// [4 1 0]
+}
@@ -0,0 +1,35 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+func TestStronglyConnectedLongCycle(t *testing.T) {
+	// a single cycle long enough that it would be a problem for
+	// a recursive implementation.
+	const n = 1e5
+	nodes := make([]dfNode, n)
+	for i := range nodes {
+		nodes[i].num = i
+		nodes[i].nbs = []graph2.Node{&nodes[(i+1)%n]}
+	}
+	c := search.StronglyConnected([]graph2.Node{&nodes[n/2]})
+	if len(c) != 1 || len(c[0]) != n {
+		t.Fatal(len(c), "components")
+	}
+}
+
+func TestStronglyConnectedIsolated(t *testing.T) {
+	a := &dfNode{num: 1}
+	b := &dfNode{num: 2}
+	c := search.StronglyConnected([]graph2.Node{a, b, a})
+	if len(c) != 2 || len(c[0]) != 1 || len(c[1]) != 1 {
+		t.Fatal(c)
+	}
+}
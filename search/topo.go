@@ -0,0 +1,256 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+)
+
+// CycleError is the error returned by the topological sort functions when
+// the graph is not acyclic.
+//
+// Cycle lists the nodes of a cycle found in the graph in path order.
+// The last node has an arc to the first node.
+type CycleError struct {
+	Cycle []graph2.Node
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprint("graph has a cycle: ", e.Cycle)
+}
+
+// TopoSortKahn returns the nodes of a directed acyclic graph in topological
+// order, using Kahn's algorithm.
+//
+// Argument nodes must hold all nodes of the graph.  Arcs leading to nodes
+// not in the argument list are ignored.  In the order returned, every node
+// appears before all nodes it has arcs to.  Where there is a choice, nodes
+// are ordered as they appear in the argument list.
+//
+// If the graph has a cycle, TopoSortKahn returns a nil order and an error
+// of type *CycleError.
+func TopoSortKahn(nodes []graph2.Node) (order []graph2.Node, err error) {
+	k := newKahn(nodes)
+	order = make([]graph2.Node, 0, len(k.nodes))
+	q := k.sources()
+	for len(q) > 0 {
+		nd := q[0]
+		q = q[1:]
+		order = append(order, nd)
+		k.release(nd, func(nb graph2.Node) {
+			q = append(q, nb)
+		})
+	}
+	if len(order) < len(k.nodes) {
+		return nil, k.cycle()
+	}
+	return order, nil
+}
+
+// TopoSortLex returns the nodes of a directed acyclic graph in the
+// lexicographically smallest topological order.
+//
+// TopoSortLex works like TopoSortKahn but where there is a choice, it
+// chooses the least node as ordered by the less function.  The result
+// is thus independent of the order of the argument list.
+//
+// If the graph has a cycle, TopoSortLex returns a nil order and an error
+// of type *CycleError.
+func TopoSortLex(nodes []graph2.Node, less func(a, b graph2.Node) bool) (order []graph2.Node, err error) {
+	k := newKahn(nodes)
+	order = make([]graph2.Node, 0, len(k.nodes))
+	h := &nodeHeap{k.sources(), less}
+	heap.Init(h)
+	for len(h.nodes) > 0 {
+		nd := heap.Pop(h).(graph2.Node)
+		order = append(order, nd)
+		k.release(nd, func(nb graph2.Node) {
+			heap.Push(h, nb)
+		})
+	}
+	if len(order) < len(k.nodes) {
+		return nil, k.cycle()
+	}
+	return order, nil
+}
+
+// TopoSortDFS returns the nodes of a directed acyclic graph in topological
+// order, using depth first search.
+//
+// Argument nodes must hold all nodes of the graph.  Arcs leading to nodes
+// not in the argument list are ignored.  The order returned is reverse
+// postorder of a depth first search started from each node in turn.
+//
+// If the graph has a cycle, TopoSortDFS returns a nil order and an error
+// of type *CycleError.
+func TopoSortDFS(nodes []graph2.Node) (order []graph2.Node, err error) {
+	sub := &subgraph{make(map[graph2.Node]struct{}, len(nodes))}
+	for _, nd := range nodes {
+		sub.member[nd] = struct{}{}
+	}
+	roots := make([]graph2.Node, len(nodes))
+	for i, nd := range nodes {
+		roots[i] = subNode{nd, sub}
+	}
+	order = make([]graph2.Node, len(sub.member))
+	i := len(order)
+	var path []graph2.Node // nodes discovered but not finished
+	DepthFirstEvents(roots, DFEvents{
+		Discover: func(nd graph2.Node, _ int) bool {
+			path = append(path, nd.(subNode).Node)
+			return true
+		},
+		Finish: func(nd graph2.Node, _ int) bool {
+			// fill order from the end.
+			path = path[:len(path)-1]
+			i--
+			order[i] = nd.(subNode).Node
+			return true
+		},
+		Edge: func(_, to graph2.Node, kind EdgeKind) bool {
+			if kind != BackEdge {
+				return true
+			}
+			// the path from to on is a cycle.
+			j := len(path) - 1
+			for path[j] != to.(subNode).Node {
+				j--
+			}
+			err = &CycleError{append([]graph2.Node{}, path[j:]...)}
+			return false
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// subgraph is a set of member nodes.
+type subgraph struct {
+	member map[graph2.Node]struct{}
+}
+
+// subNode adapts a graph2.Node to a subgraph.  It visits only adjacent
+// nodes that are members of the subgraph.
+type subNode struct {
+	graph2.Node
+	sub *subgraph
+}
+
+func (n subNode) VisitAdjNodes(v graph2.AdjNodeVisitor) bool {
+	return n.Node.VisitAdjNodes(func(nb graph2.Node) bool {
+		if _, ok := n.sub.member[nb]; !ok {
+			return true
+		}
+		return v(subNode{nb, n.sub})
+	})
+}
+
+// kahn holds data common to Kahn's algorithm variants.
+type kahn struct {
+	nodes []graph2.Node       // argument nodes, without duplicates
+	in    map[graph2.Node]int // remaining in-degree
+}
+
+func newKahn(nodes []graph2.Node) *kahn {
+	k := &kahn{in: make(map[graph2.Node]int, len(nodes))}
+	for _, nd := range nodes {
+		if _, ok := k.in[nd]; !ok {
+			k.in[nd] = 0
+			k.nodes = append(k.nodes, nd)
+		}
+	}
+	for _, nd := range k.nodes {
+		nd.VisitAdjNodes(func(nb graph2.Node) bool {
+			if d, ok := k.in[nb]; ok {
+				k.in[nb] = d + 1
+			}
+			return true
+		})
+	}
+	return k
+}
+
+// sources returns nodes with no inward arcs.
+func (k *kahn) sources() (s []graph2.Node) {
+	for _, nd := range k.nodes {
+		if k.in[nd] == 0 {
+			s = append(s, nd)
+		}
+	}
+	return
+}
+
+// release removes arcs from nd, calling f for each node left with no
+// remaining inward arcs.
+func (k *kahn) release(nd graph2.Node, f func(graph2.Node)) {
+	nd.VisitAdjNodes(func(nb graph2.Node) bool {
+		if d, ok := k.in[nb]; ok {
+			if k.in[nb] = d - 1; d == 1 {
+				f(nb)
+			}
+		}
+		return true
+	})
+}
+
+// cycle finds a cycle among nodes that could not be released.
+func (k *kahn) cycle() error {
+	// every remaining node has an arc from some other remaining node.
+	// following such arcs backward must eventually repeat a node.
+	pred := map[graph2.Node]graph2.Node{}
+	var nd graph2.Node
+	for _, n1 := range k.nodes {
+		if k.in[n1] == 0 {
+			continue
+		}
+		nd = n1
+		n1.VisitAdjNodes(func(n2 graph2.Node) bool {
+			if k.in[n2] > 0 {
+				pred[n2] = n1
+			}
+			return true
+		})
+	}
+	seen := map[graph2.Node]struct{}{}
+	for {
+		if _, ok := seen[nd]; ok {
+			break // nd is on a cycle
+		}
+		seen[nd] = struct{}{}
+		nd = pred[nd]
+	}
+	// collect the cycle backward, then reverse it.
+	c := []graph2.Node{nd}
+	for p := pred[nd]; p != nd; p = pred[p] {
+		c = append(c, p)
+	}
+	for i, j := 0, len(c)-1; i < j; i, j = i+1, j-1 {
+		c[i], c[j] = c[j], c[i]
+	}
+	return &CycleError{c}
+}
+
+// nodeHeap implements container/heap for TopoSortLex.
+type nodeHeap struct {
+	nodes []graph2.Node
+	less  func(a, b graph2.Node) bool
+}
+
+func (h nodeHeap) Len() int           { return len(h.nodes) }
+func (h nodeHeap) Less(i, j int) bool { return h.less(h.nodes[i], h.nodes[j]) }
+func (h nodeHeap) Swap(i, j int)      { h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i] }
+func (h *nodeHeap) Push(x interface{}) {
+	h.nodes = append(h.nodes, x.(graph2.Node))
+}
+func (h *nodeHeap) Pop() interface{} {
+	last := len(h.nodes) - 1
+	nd := h.nodes[last]
+	h.nodes = h.nodes[:last]
+	return nd
+}
@@ -0,0 +1,68 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// The topological sort functions use the same node type as DepthFirst.
+
+// dfGraph constructs nodes numbered 0 through n-1 and links them by arcs
+// listed as pairs of node numbers.
+func dfGraph(n int, arcs ...[2]int) []graph2.Node {
+	nd := make([]*dfNode, n)
+	g := make([]graph2.Node, n)
+	for i := range nd {
+		nd[i] = &dfNode{num: i}
+		g[i] = nd[i]
+	}
+	for _, a := range arcs {
+		nd[a[0]].nbs = append(nd[a[0]].nbs, nd[a[1]])
+	}
+	return g
+}
+
+func ExampleTopoSortKahn() {
+	g := dfGraph(6, [2]int{5, 2}, [2]int{5, 0}, [2]int{4, 0}, [2]int{4, 1},
+		[2]int{2, 3}, [2]int{3, 1})
+	fmt.Println(search.TopoSortKahn(g))
+	// Output:
+	// [4 5 2 0 3 1] <nil>
+}
+
+func ExampleTopoSortDFS() {
+	g := dfGraph(6, [2]int{5, 2}, [2]int{5, 0}, [2]int{4, 0}, [2]int{4, 1},
+		[2]int{2, 3}, [2]int{3, 1})
+	fmt.Println(search.TopoSortDFS(g))
+	// Output:
+	// [5 4 2 3 1 0] <nil>
+}
+
+func ExampleTopoSortLex() {
+	g := dfGraph(6, [2]int{5, 2}, [2]int{5, 0}, [2]int{4, 0}, [2]int{4, 1},
+		[2]int{2, 3}, [2]int{3, 1})
+	less := func(a, b graph2.Node) bool {
+		return a.(*dfNode).num < b.(*dfNode).num
+	}
+	fmt.Println(search.TopoSortLex(g, less))
+	// Output:
+	// [4 5 0 2 3 1] <nil>
+}
+
+func ExampleCycleError() {
+	g := dfGraph(5, [2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3}, [2]int{3, 1},
+		[2]int{3, 4})
+	_, err := search.TopoSortDFS(g)
+	fmt.Println(err)
+	if ce, ok := err.(*search.CycleError); ok {
+		fmt.Println(len(ce.Cycle), "nodes in cycle")
+	}
+	// Output:
+	// graph has a cycle: [1 2 3]
+	// 3 nodes in cycle
+}
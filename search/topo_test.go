@@ -0,0 +1,121 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+type topoSort func([]graph2.Node) ([]graph2.Node, error)
+
+func topoSorts() map[string]topoSort {
+	return map[string]topoSort{
+		"Kahn": search.TopoSortKahn,
+		"DFS":  search.TopoSortDFS,
+		"Lex": func(g []graph2.Node) ([]graph2.Node, error) {
+			return search.TopoSortLex(g, func(a, b graph2.Node) bool {
+				return a.(*dfNode).num < b.(*dfNode).num
+			})
+		},
+	}
+}
+
+// randDAG generates a random DAG with arcs only from lower to higher
+// numbered nodes, returned in random order.  The same seed gives the same
+// DAG.
+func randDAG(n, m int, seed int64) []graph2.Node {
+	r := rand.New(rand.NewSource(seed))
+	arcs := make([][2]int, m)
+	for i := range arcs {
+		a, b := r.Intn(n), r.Intn(n-1)
+		if b >= a {
+			b++
+		} else {
+			a, b = b, a
+		}
+		arcs[i] = [2]int{a, b}
+	}
+	g := dfGraph(n, arcs...)
+	r.Shuffle(n, func(i, j int) { g[i], g[j] = g[j], g[i] })
+	return g
+}
+
+func TestTopoSortDAG(t *testing.T) {
+	g := randDAG(200, 1000, 1)
+	for name, sort := range topoSorts() {
+		order, err := sort(g)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if len(order) != len(g) {
+			t.Fatal(name, "order has", len(order), "nodes")
+		}
+		pos := map[graph2.Node]int{}
+		for i, nd := range order {
+			pos[nd] = i
+		}
+		for _, n1 := range g {
+			n1.VisitAdjNodes(func(n2 graph2.Node) bool {
+				if pos[n1] >= pos[n2] {
+					t.Fatal(name, "arc", n1, n2, "out of order")
+				}
+				return true
+			})
+		}
+	}
+}
+
+func TestTopoSortCycle(t *testing.T) {
+	g := randDAG(200, 1000, 2)
+	// close a cycle with an arc from the highest numbered node back to
+	// some node that leads to it.
+	var hi, lo *dfNode
+	for _, nd := range g {
+		switch d := nd.(*dfNode); d.num {
+		case 199:
+			hi = d
+		case 0:
+			lo = d
+		}
+	}
+	lo.nbs = append(lo.nbs, hi)
+	hi.nbs = append(hi.nbs, lo)
+	for name, sort := range topoSorts() {
+		order, err := sort(g)
+		if order != nil {
+			t.Fatal(name, "expected nil order")
+		}
+		ce, ok := err.(*search.CycleError)
+		if !ok {
+			t.Fatal(name, "expected CycleError, got", err)
+		}
+		c := ce.Cycle
+		for i, n1 := range c {
+			n2 := c[(i+1)%len(c)]
+			found := false
+			n1.VisitAdjNodes(func(nb graph2.Node) bool {
+				found = found || nb == n2
+				return true
+			})
+			if !found {
+				t.Fatal(name, "bad cycle", c)
+			}
+		}
+	}
+}
+
+func TestTopoSortSelfLoop(t *testing.T) {
+	g := dfGraph(3, [2]int{0, 1}, [2]int{1, 1}, [2]int{1, 2})
+	for name, sort := range topoSorts() {
+		_, err := sort(g)
+		ce, ok := err.(*search.CycleError)
+		if !ok || len(ce.Cycle) != 1 || ce.Cycle[0] != g[1] {
+			t.Fatal(name, err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import (
+	"github.com/soniakeys/graph2"
+)
+
+// BoundaryArc is an arc leading from a node of one Voronoi cell to a node
+// of another.
+type BoundaryArc struct {
+	From graph2.HalfNode
+	Ed   interface{} // arc or edge
+	To   graph2.HalfNode
+}
+
+// Voronoi partitions a graph into cells, one for each seed node.
+//
+// Each node reachable from any seed is assigned to the cell of the seed
+// nearest to it, where distance is the shortest path length from the seed.
+// Ties are broken arbitrarily.  All cells are found with a single Dijkstra
+// search started from all seeds at once.
+//
+// Nodes and edges must satisfy requirements documented for
+// DijkstraShortestPath.
+//
+// Returned map cell has a key for each node reachable from any seed.  The
+// element value is the seed of the node's cell.  Each seed is in its own
+// cell.  Map dist has the same keys, with element values of distances from
+// the seeds.  Boundary lists the arcs leading from a node of one cell to a
+// node of another, in no particular order.  For an undirected graph, each
+// edge between cells is listed twice, once in each direction.
+func Voronoi(seeds []graph2.HalfNode) (cell map[graph2.HalfNode]graph2.HalfNode, dist map[graph2.HalfNode]float64, boundary []BoundaryArc) {
+	sources := make(map[halfNode]float64, len(seeds))
+	for _, s := range seeds {
+		sources[halfNode{s}] = 0
+	}
+	tree, _, _, _ := djk(halfNode{}, halfNode{}, true,
+		&djkOpt[halfNode, graph2.Weighted]{sources: sources})
+	cell = make(map[graph2.HalfNode]graph2.HalfNode, len(tree))
+	dist = make(map[graph2.HalfNode]float64, len(tree))
+	// find cells and distances by walking back along the tree to a node
+	// already known, then assigning nodes along the way.
+	var walk []halfNode
+	for nd := range tree {
+		for p := nd; ; {
+			if _, ok := cell[p.HalfNode]; ok {
+				break
+			}
+			from := tree[p]
+			if from.From.HalfNode == nil { // a seed
+				cell[p.HalfNode] = p.HalfNode
+				dist[p.HalfNode] = 0
+				break
+			}
+			walk = append(walk, p)
+			p = from.From
+		}
+		for i := len(walk) - 1; i >= 0; i-- {
+			p := walk[i].HalfNode
+			from := tree[walk[i]]
+			cell[p] = cell[from.From.HalfNode]
+			dist[p] = dist[from.From.HalfNode] + from.Ed.Weight()
+		}
+		walk = walk[:0]
+	}
+	for nd := range tree {
+		s := cell[nd.HalfNode]
+		nd.VisitAdjHalfs(func(h graph2.Half) {
+			if s2, ok := cell[h.To]; ok && s2 != s {
+				boundary = append(boundary, BoundaryArc{nd.HalfNode, h.Ed, h.To})
+			}
+		})
+	}
+	return
+}
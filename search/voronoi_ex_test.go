@@ -0,0 +1,61 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// Voronoi uses the same node and arc types as DijkstraShortestPath.
+
+func ExampleVoronoi() {
+	c := &dspNode{name: "c"}
+	d := &dspNode{name: "d"}
+	e := &dspNode{name: "e"}
+	f := &dspNode{name: "f"}
+	g := &dspNode{name: "g"}
+	h := &dspNode{name: "h"}
+	c.link(d, 3)
+	c.link(e, 2)
+	d.link(f, 4)
+	e.link(d, 1)
+	e.link(f, 2)
+	e.link(g, 3)
+	f.link(g, 2)
+	f.link(h, 1)
+	g.link(h, 2)
+	cell, dist, boundary := search.Voronoi([]graph2.HalfNode{c, f})
+	var s []string
+	for nd, seed := range cell {
+		s = append(s, fmt.Sprint(nd, " ", seed, " ", dist[nd]))
+	}
+	sort.Strings(s)
+	for _, s := range s {
+		fmt.Println(s)
+	}
+	s = s[:0]
+	for _, b := range boundary {
+		s = append(s, fmt.Sprint(b.From, " ", b.Ed, " ", b.To))
+	}
+	sort.Strings(s)
+	fmt.Println("boundary:")
+	for _, s := range s {
+		fmt.Println(s)
+	}
+	// Output:
+	// c c 0
+	// d c 3
+	// e c 2
+	// f f 0
+	// g f 2
+	// h f 1
+	// boundary:
+	// d 4 f
+	// e 2 f
+	// e 3 g
+}
@@ -0,0 +1,71 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+func TestVoronoi(t *testing.T) {
+	start, _ := r(1000, 3000, 66)
+	var nodes []graph2.HalfNode
+	for nd := range search.DijkstraAllPaths(start) {
+		nodes = append(nodes, nd)
+	}
+	x := rand.New(rand.NewSource(5))
+	seeds := make([]graph2.HalfNode, 5)
+	for i := range seeds {
+		seeds[i] = nodes[x.Intn(len(nodes))]
+	}
+	// brute force with a DijkstraAllPaths run per seed
+	best := map[graph2.HalfNode]float64{}
+	for _, s := range seeds {
+		tree := search.DijkstraAllPaths(s)
+		for nd := range tree {
+			d, _ := treeDist(tree, nd)
+			if b, ok := best[nd]; !ok || d < b {
+				best[nd] = d
+			}
+		}
+	}
+	cell, dist, boundary := search.Voronoi(seeds)
+	if len(cell) != len(best) || len(dist) != len(best) {
+		t.Fatal("Voronoi reached", len(cell), len(dist), "want", len(best))
+	}
+	for nd, b := range best {
+		if math.Abs(dist[nd]-b) > 1e-12 {
+			t.Fatal(nd, "dist", dist[nd], "want", b)
+		}
+		if _, d := search.DijkstraShortestPath(cell[nd], nd); math.Abs(d-b) > 1e-12 {
+			t.Fatal(nd, "in cell", cell[nd], "at", d, "want", b)
+		}
+	}
+	// every arc between cells is in boundary exactly once
+	bm := map[search.BoundaryArc]int{}
+	for _, b := range boundary {
+		if cell[b.From] == cell[b.To] {
+			t.Fatal("boundary arc within cell", b)
+		}
+		bm[b]++
+	}
+	n := 0
+	for nd := range cell {
+		nd.VisitAdjHalfs(func(h graph2.Half) {
+			if cell[h.To] != cell[nd] {
+				n++
+				if bm[search.BoundaryArc{nd, h.Ed, h.To}] != 1 {
+					t.Fatal("boundary arc missing or repeated", nd, h)
+				}
+			}
+		})
+	}
+	if n != len(boundary) {
+		t.Fatal(len(boundary), "boundary arcs, want", n)
+	}
+}
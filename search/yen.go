@@ -0,0 +1,142 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search
+
+import (
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/internal"
+)
+
+// KShortestPaths finds the k shortest loopless paths between two nodes.
+//
+// It implements Yen's algorithm.  Each path after the first is found as
+// a deviation from a previously found path.  A Dijkstra search finds a
+// "spur" path from some node of the previous path to the end node, with
+// the "root" path up to the spur node masked out along with arcs followed
+// by previous paths sharing the same root.
+//
+// Arguments start and end must implement graph2.HalfNode.  Edges connecting
+// nodes must implement graph2.Weighted.  Weights must be non-negative and
+// must not be an Inf or NaN.  To be distinguished from one another, arcs
+// must be of comparable types.
+//
+// Paths are returned in order of increasing length, with the lengths in
+// dists.  Paths of equal length are ordered by number of arcs.  Each path
+// is in the form returned by DijkstraShortestPath.  Fewer than k paths are
+// returned if fewer than k loopless paths exist.  If the end node cannot
+// be reached from the start node, both results are nil.
+func KShortestPaths(start, end graph2.HalfNode, k int) (paths [][]graph2.Half, dists []float64) {
+	if start == nil {
+		return nil, nil
+	}
+	p, dists := yen(halfNode{start}, halfNode{end}, k)
+	if p == nil {
+		return nil, nil
+	}
+	paths = make([][]graph2.Half, len(p))
+	for i, path := range p {
+		paths[i] = halfPath(path)
+	}
+	return paths, dists
+}
+
+// yenPath is a candidate path for Yen's algorithm.
+type yenPath[N, E any] struct {
+	path []graph2.HalfOf[N, E]
+	dist float64
+}
+
+func yen[N graph2.HalfNodeOf[N, E], E graph2.Weighted](start, end N, k int) ([][]graph2.HalfOf[N, E], []float64) {
+	if k < 1 {
+		return nil, nil
+	}
+	_, path, dist, _ := djk(start, end, false, nil)
+	if path == nil {
+		return nil, nil
+	}
+	a := []yenPath[N, E]{{path, dist}} // paths found
+	var b []yenPath[N, E]              // candidate paths
+	for len(a) < k {
+		prev := a[len(a)-1].path
+		rootDist := 0.
+		for j := 0; j < len(prev)-1; j++ {
+			if j > 0 {
+				rootDist += prev[j].Ed.Weight()
+			}
+			root := prev[:j+1]
+			spurNode := prev[j].To
+			o := &djkOpt[N, E]{
+				nodeMask: map[N]struct{}{},
+				arcMask:  map[N][]graph2.HalfOf[N, E]{},
+			}
+			// mask the next arc of each found path sharing the root
+			for _, p := range a {
+				if len(p.path) > j+1 && samePath(p.path[:j+1], root) {
+					o.arcMask[spurNode] =
+						append(o.arcMask[spurNode], p.path[j+1])
+				}
+			}
+			// mask root path nodes so the spur path cannot loop
+			for _, h := range root[:j] {
+				o.nodeMask[h.To] = struct{}{}
+			}
+			_, spur, spurDist, _ := djk(spurNode, end, false, o)
+			if spur == nil {
+				continue
+			}
+			c := make([]graph2.HalfOf[N, E], j+len(spur))
+			copy(c, root)
+			copy(c[j+1:], spur[1:])
+			if !hasPath(b, c) {
+				b = append(b, yenPath[N, E]{c, rootDist + spurDist})
+			}
+		}
+		if len(b) == 0 {
+			break
+		}
+		// move best candidate to a
+		best := 0
+		for i, c := range b {
+			if c.dist < b[best].dist ||
+				c.dist == b[best].dist && len(c.path) < len(b[best].path) {
+				best = i
+			}
+		}
+		a = append(a, b[best])
+		last := len(b) - 1
+		b[best] = b[last]
+		b = b[:last]
+	}
+	paths := make([][]graph2.HalfOf[N, E], len(a))
+	dists := make([]float64, len(a))
+	for i, p := range a {
+		paths[i] = p.path
+		dists[i] = p.dist
+	}
+	return paths, dists
+}
+
+// samePath returns true if paths p and q have the same nodes and arcs.
+// Arcs are compared as interface values with internal.Equal.
+func samePath[N comparable, E any](p, q []graph2.HalfOf[N, E]) bool {
+	if len(p) != len(q) {
+		return false
+	}
+	for i, h := range p {
+		if h.To != q[i].To || !internal.Equal(h.Ed, q[i].Ed) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPath returns true if candidate list b already contains path p.
+func hasPath[N comparable, E any](b []yenPath[N, E], p []graph2.HalfOf[N, E]) bool {
+	for _, c := range b {
+		if samePath(c.path, p) {
+			return true
+		}
+	}
+	return false
+}
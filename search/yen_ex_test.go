@@ -0,0 +1,39 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/graph2/search"
+)
+
+// KShortestPaths uses the same node and arc types as DijkstraShortestPath.
+
+func ExampleKShortestPaths() {
+	c := &dspNode{name: "c"}
+	d := &dspNode{name: "d"}
+	e := &dspNode{name: "e"}
+	f := &dspNode{name: "f"}
+	g := &dspNode{name: "g"}
+	h := &dspNode{name: "h"}
+	c.link(d, 3)
+	c.link(e, 2)
+	d.link(f, 4)
+	e.link(d, 1)
+	e.link(f, 2)
+	e.link(g, 3)
+	f.link(g, 2)
+	f.link(h, 1)
+	g.link(h, 2)
+	paths, dists := search.KShortestPaths(c, h, 4)
+	for i, p := range paths {
+		fmt.Println(dists[i], p)
+	}
+	// Output:
+	// 5 [{<nil> c} {2 e} {2 f} {1 h}]
+	// 7 [{<nil> c} {2 e} {3 g} {2 h}]
+	// 8 [{<nil> c} {3 d} {4 f} {1 h}]
+	// 8 [{<nil> c} {2 e} {1 d} {4 f} {1 h}]
+}
@@ -0,0 +1,131 @@
+// Copyright 2016 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package search_test
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph2"
+	"github.com/soniakeys/graph2/search"
+)
+
+// simplePathDists enumerates lengths of all loopless paths from start
+// to end.
+func simplePathDists(start, end *stNode) (d []float64) {
+	on := map[*stNode]bool{}
+	var f func(*stNode, float64)
+	f = func(n *stNode, dist float64) {
+		if n == end {
+			d = append(d, dist)
+			return
+		}
+		on[n] = true
+		for _, a := range n.nbs {
+			if !on[a.to] {
+				f(a.to, dist+a.weight)
+			}
+		}
+		on[n] = false
+	}
+	f(start, 0)
+	sort.Float64s(d)
+	return
+}
+
+func TestKShortestPaths(t *testing.T) {
+	for _, seed := range []int64{62, 63, 64, 65} {
+		start, end := r(12, 30, seed)
+		want := simplePathDists(start, end)
+		const k = 10
+		paths, dists := search.KShortestPaths(start, end, k)
+		if len(want) > k {
+			want = want[:k]
+		}
+		if len(dists) != len(want) || len(paths) != len(want) {
+			t.Fatal("seed", seed, "got", len(paths), "paths, want", len(want))
+		}
+		for i, p := range paths {
+			if math.Abs(dists[i]-want[i]) > 1e-9 {
+				t.Fatal("seed", seed, "path", i, "length", dists[i],
+					"want", want[i])
+			}
+			checkSimplePath(t, p, start, end, dists[i])
+		}
+	}
+}
+
+// checkSimplePath verifies that p is a loopless path of arcs from start
+// to end with length dist.
+func checkSimplePath(t *testing.T, p []graph2.Half, start, end *stNode, dist float64) {
+	if p[0].To != start || p[len(p)-1].To != end {
+		t.Fatal("path does not connect start and end:", p)
+	}
+	seen := map[graph2.HalfNode]bool{}
+	d := 0.
+	for i, h := range p {
+		if seen[h.To] {
+			t.Fatal("loop in path", p)
+		}
+		seen[h.To] = true
+		if i == 0 {
+			continue
+		}
+		a := h.Ed.(stArc)
+		found := false
+		for _, nb := range p[i-1].To.(*stNode).nbs {
+			found = found || nb == a
+		}
+		if !found || a.to != h.To {
+			t.Fatal("bad arc in path", p)
+		}
+		d += a.weight
+	}
+	if math.Abs(d-dist) > 1e-9 {
+		t.Fatal("path sums to", d, "returned length", dist)
+	}
+}
+
+// sliceArc is an arc type that is not comparable.
+type sliceArc []float64
+
+func (a sliceArc) Weight() float64 { return a[0] }
+
+// tagArc is a comparable arc type, but comparing two tagArcs panics with
+// ==  when their tags hold values that are not comparable.
+type tagArc struct {
+	w   float64
+	tag interface{}
+}
+
+func (a tagArc) Weight() float64 { return a.w }
+
+func TestKShortestPathsNotComparable(t *testing.T) {
+	// comparing arcs must not panic.  such arcs cannot be masked and paths
+	// with them cannot be told apart, but the shortest path is still found.
+	for _, arc := range []func(float64) graph2.Weighted{
+		func(w float64) graph2.Weighted { return sliceArc{w} },
+		func(w float64) graph2.Weighted { return tagArc{w, []int{}} },
+	} {
+		nodes := make([]*dapNode, 4)
+		for i := range nodes {
+			nodes[i] = &dapNode{name: string(rune('a' + i))}
+		}
+		link := func(n1, n2 int, w float64) {
+			nodes[n1].nbs = append(nodes[n1].nbs, graph2.Half{arc(w), nodes[n2]})
+		}
+		link(0, 1, 1)
+		link(1, 3, 1)
+		link(0, 2, 2)
+		link(2, 3, 2)
+		paths, dists := search.KShortestPaths(nodes[0], nodes[3], 3)
+		if len(paths) == 0 {
+			t.Fatal("no paths")
+		}
+		if dists[0] != 2 || len(paths[0]) != 3 || paths[0][1].To != nodes[1] {
+			t.Fatal("shortest path", paths[0], dists[0])
+		}
+	}
+}